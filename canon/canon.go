@@ -0,0 +1,141 @@
+// Package canon builds the canonical strings that signed query strings are
+// verified against. It splits a raw query into decoded key/value pairs,
+// filters and sorts them, and writes them in the formats used by the
+// platforms: newline-joined (Telegram, MAX), '&'-joined and escaped (VK Mini
+// Apps) and concatenated with a secret (VK Shop, OK).
+//
+// The tma, maxma, vkma, vkmashop, okapp and okpay verifiers are built on
+// it, and so is package scheme, which describes new signature schemes as
+// data.
+// Iterating, sorting and writing do not allocate.
+package canon
+
+import (
+	"sync"
+)
+
+// insertionSortMax is the largest slice sorted with insertion sort;
+// longer slices are merge sorted in runs of this length.
+const insertionSortMax = 12
+
+// maxPooledPairs is the largest merge buffer returned to sortPool.
+const maxPooledPairs = 256
+
+// sortPool holds the merge buffers of Sort.
+var sortPool = sync.Pool{
+	New: func() any { return new(Pairs) },
+}
+
+// Pair is a decoded query parameter.
+type Pair struct {
+	Key   string
+	Value string
+}
+
+// Pairs is a list of query parameters.
+type Pairs []Pair
+
+func (s Pairs) Len() int           { return len(s) }
+func (s Pairs) Less(i, j int) bool { return s[i].Key < s[j].Key }
+func (s Pairs) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// Sort sorts s by key, keeping the order of equal keys.
+//
+// Keys repeat only in queries accepted outside strict mode, but they are
+// signed in the order received, so the sort must be stable. Short slices
+// use insertion sort; longer ones an O(n log n) merge sort with a pooled
+// buffer, which does not allocate up to maxPooledPairs pairs.
+func (s Pairs) Sort() {
+	if len(s) <= insertionSortMax {
+		insertionSort(s)
+		return
+	}
+
+	bufPtr := sortPool.Get().(*Pairs)
+	buf := *bufPtr
+	if cap(buf) < len(s) {
+		buf = make(Pairs, len(s))
+	}
+	buf = buf[:len(s)]
+	mergeSort(s, buf)
+
+	if cap(buf) > maxPooledPairs {
+		return // Let oversized buffers be collected
+	}
+	for i := range buf {
+		buf[i] = Pair{} // Do not keep the strings alive
+	}
+	*bufPtr = buf[:0]
+	sortPool.Put(bufPtr)
+}
+
+// insertionSort sorts s by key, keeping the order of equal keys.
+func insertionSort(s Pairs) {
+	for i := 1; i < len(s); i++ {
+		p := s[i]
+		j := i - 1
+		for j >= 0 && s[j].Key > p.Key {
+			s[j+1] = s[j]
+			j--
+		}
+		s[j+1] = p
+	}
+}
+
+// mergeSort sorts s by key, keeping the order of equal keys, using buf
+// of the same length as scratch space.
+func mergeSort(s, buf Pairs) {
+	n := len(s)
+	for lo := 0; lo < n; lo += insertionSortMax {
+		hi := lo + insertionSortMax
+		if hi > n {
+			hi = n
+		}
+		insertionSort(s[lo:hi])
+	}
+
+	src, dst := s, buf
+	for width := insertionSortMax; width < n; width *= 2 {
+		for lo := 0; lo < n; lo += 2 * width {
+			mid, hi := lo+width, lo+2*width
+			if mid > n {
+				mid = n
+			}
+			if hi > n {
+				hi = n
+			}
+			merge(dst[lo:hi], src[lo:mid], src[mid:hi])
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
+
+// merge merges the sorted a and b into dst, taking from a on equal keys.
+func merge(dst, a, b Pairs) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j].Key < a[i].Key {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// Get returns the value of the first pair with the given key.
+func (s Pairs) Get(key string) (string, bool) {
+	for _, p := range s {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,84 @@
+package canon
+
+import (
+	"unsafe"
+)
+
+// Unescape is a lightweight replacement for url.QueryUnescape.
+//
+// It decodes %XX sequences and replaces '+' with space. Strings without
+// escapes are returned as is; the others are decoded by appending to buf,
+// so strings returned by earlier calls sharing buf stay intact. The result
+// points into buf and is valid while buf's array is not reused.
+// Returns false if s contains invalid percent-encoding.
+func Unescape(s string, buf *[]byte) (string, bool) {
+	needsDecode := false
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' || s[i] == '+' {
+			needsDecode = true
+			break
+		}
+	}
+	if !needsDecode {
+		return s, true
+	}
+
+	b := *buf
+	offset := len(b)
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '%':
+			if i+2 >= len(s) {
+				return "", false
+			}
+			hi, lo := unhex(s[i+1]), unhex(s[i+2])
+			if hi == 255 || lo == 255 {
+				return "", false
+			}
+			b = append(b, hi<<4|lo)
+			i += 3
+		case '+':
+			b = append(b, ' ')
+			i++
+		default:
+			b = append(b, s[i])
+			i++
+		}
+	}
+	*buf = b
+
+	return unsafe.String(&b[offset], len(b)-offset), true
+}
+
+// AppendEscape appends s to dst with RFC 3986 percent-encoding: every
+// byte except letters, digits and "-_.~" is escaped.
+func AppendEscape(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'a' && c <= 'z') ||
+			(c >= 'A' && c <= 'Z') ||
+			(c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			dst = append(dst, c)
+		} else {
+			dst = append(dst, '%', upperhex[c>>4], upperhex[c&15])
+		}
+	}
+	return dst
+}
+
+const upperhex = "0123456789ABCDEF"
+
+// unhex converts an ASCII hex digit into its value, or 255.
+func unhex(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return 255
+	}
+}
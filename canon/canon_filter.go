@@ -0,0 +1,48 @@
+package canon
+
+import (
+	"strings"
+)
+
+// Filter reports whether a parameter takes part in the canonical string.
+type Filter func(key string) bool
+
+// All includes every parameter.
+func All(string) bool { return true }
+
+// Include returns a filter that accepts only the given keys.
+func Include(keys ...string) Filter {
+	return func(key string) bool { return contains(keys, key) }
+}
+
+// Exclude returns a filter that accepts every key but the given ones,
+// such as the signature parameter.
+func Exclude(keys ...string) Filter {
+	return func(key string) bool { return !contains(keys, key) }
+}
+
+// Prefix returns a filter that accepts keys starting with prefix.
+func Prefix(prefix string) Filter {
+	return func(key string) bool { return strings.HasPrefix(key, prefix) }
+}
+
+// And returns a filter that accepts keys accepted by every filter.
+func And(filters ...Filter) Filter {
+	return func(key string) bool {
+		for _, f := range filters {
+			if !f(key) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
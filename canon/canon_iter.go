@@ -0,0 +1,99 @@
+package canon
+
+import (
+	"strings"
+
+	"github.com/elum-utils/sign"
+)
+
+// Options controls how a raw query is split into pairs.
+type Options struct {
+	// RejectBare reports a parameter without '=' as an error instead of
+	// skipping it
+	RejectBare bool
+
+	// Limits bounds the input; nil means sign.DefaultLimits
+	Limits *sign.Limits
+}
+
+// Iterator walks the '&'-separated pairs of a raw query, decoding keys and
+// values without allocating beyond the growth of its buffer.
+//
+// A leading '?' is skipped, so a URL's "?query" can be passed as is.
+//
+//	it := canon.NewIterator(rawQuery, make([]byte, 0, 256), canon.Options{})
+//	for p, ok := it.Next(); ok; p, ok = it.Next() {
+//		// use p.Key and p.Value
+//	}
+//	if err := it.Err(); err != nil {
+//		// malformed or oversized input
+//	}
+type Iterator struct {
+	rest   string
+	buf    []byte
+	limits *sign.Limits
+	opts   Options
+	err    error
+}
+
+// NewIterator returns an iterator over the pairs of raw. Decoded keys and
+// values are appended to buf, and strings returned earlier stay valid.
+// Input exceeding the limits fails on the first call to Next.
+func NewIterator(raw string, buf []byte, opts Options) Iterator {
+	it := Iterator{rest: raw, buf: buf, limits: opts.Limits, opts: opts}
+	if it.limits == nil {
+		it.limits = &sign.DefaultLimits
+	}
+	if len(it.rest) > 0 && it.rest[0] == '?' {
+		it.rest = it.rest[1:]
+	}
+	if !it.limits.AllowsQuery(raw) {
+		it.err = sign.ErrTooLarge
+	}
+	return it
+}
+
+// Next returns the next decoded pair. It returns false at the end of the
+// input or on an error, reported by Err:
+//   - sign.ErrMalformedPair for invalid escapes, and for a parameter
+//     without '=' if Options.RejectBare is set
+//   - sign.ErrTooLarge for input or pairs exceeding the limits
+func (it *Iterator) Next() (Pair, bool) {
+	for it.err == nil && len(it.rest) > 0 {
+		param := it.rest
+		if i := strings.IndexByte(it.rest, '&'); i >= 0 {
+			param, it.rest = it.rest[:i], it.rest[i+1:]
+		} else {
+			it.rest = ""
+		}
+
+		eq := strings.IndexByte(param, '=')
+		if eq < 0 {
+			if it.opts.RejectBare {
+				it.err = sign.ErrMalformedPair
+			}
+			continue
+		}
+		if !it.limits.AllowsPair(eq, len(param)-eq-1) {
+			it.err = sign.ErrTooLarge
+			break
+		}
+
+		key, ok1 := Unescape(param[:eq], &it.buf)
+		val, ok2 := Unescape(param[eq+1:], &it.buf)
+		if !ok1 || !ok2 {
+			it.err = sign.ErrMalformedPair
+			break
+		}
+		return Pair{Key: key, Value: val}, true
+	}
+	return Pair{}, false
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error { return it.err }
+
+// Buffer returns the decode buffer, grown by the decoded keys and values.
+// Decoded strings point into it, or into the array of the buffer passed to
+// NewIterator if it did not grow.
+func (it *Iterator) Buffer() []byte { return it.buf }
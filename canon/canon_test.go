@@ -0,0 +1,192 @@
+package canon
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/internal/race"
+)
+
+func collect(raw string, opts Options) (Pairs, error) {
+	var pairs Pairs
+	it := NewIterator(raw, nil, opts)
+	for p, ok := it.Next(); ok; p, ok = it.Next() {
+		pairs = append(pairs, p)
+	}
+	return pairs, it.Err()
+}
+
+func TestIterator(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		opts    Options
+		want    Pairs
+		wantErr error
+	}{
+		{"empty", "", Options{}, nil, nil},
+		{"question mark", "?a=1&b=", Options{}, Pairs{{"a", "1"}, {"b", ""}}, nil},
+		{"escapes", "k%20y=a+b%26c&x=%D0%96", Options{}, Pairs{{"k y", "a b&c"}, {"x", "Ж"}}, nil},
+		{"bare skipped", "a=1&bare&&b=2", Options{}, Pairs{{"a", "1"}, {"b", "2"}}, nil},
+		{"bare rejected", "a=1&bare", Options{RejectBare: true}, Pairs{{"a", "1"}}, sign.ErrMalformedPair},
+		{"bad escape", "a=%zz&b=1", Options{}, nil, sign.ErrMalformedPair},
+		{"value with =", "a=b=c", Options{}, Pairs{{"a", "b=c"}}, nil},
+		{"too many params", strings.Repeat("a=1&", 70), Options{}, nil, sign.ErrTooLarge},
+		{"key too long", "a=1&" + strings.Repeat("k", 100) + "=1", Options{}, Pairs{{"a", "1"}}, sign.ErrTooLarge},
+		{"custom limits", "abc=1", Options{Limits: &sign.Limits{MaxKeyLength: 2}}, nil, sign.ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := collect(tt.raw, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Err() = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pairs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIterator_Allocs(t *testing.T) {
+	raw := "vk_user_id=494075&vk_app_id=6736218&vk_platform=andr%26oid&sign=abc"
+	buf := make([]byte, 0, 64)
+	pairs := make(Pairs, 0, 8)
+	dst := make([]byte, 0, 256)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		pairs = pairs[:0]
+		it := NewIterator(raw, buf[:0], Options{})
+		for p, ok := it.Next(); ok; p, ok = it.Next() {
+			if Prefix("vk_")(p.Key) {
+				pairs = append(pairs, p)
+			}
+		}
+		pairs.Sort()
+		dst = AppendQuery(dst[:0], pairs)
+	})
+	if allocs != 0 {
+		t.Errorf("allocs = %v, want 0", allocs)
+	}
+}
+
+func TestSort_Allocs(t *testing.T) {
+	if race.Enabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+
+	keys := []string{"vk_user_id", "vk_app_id", "vk_ts", "vk_ref", "sign", "user", "hash", "auth_date", "chat_type"}
+	pairs := make(Pairs, maxPooledPairs)
+	allocs := testing.AllocsPerRun(100, func() {
+		for i := range pairs {
+			pairs[i] = Pair{Key: keys[(i*7)%len(keys)]}
+		}
+		pairs.Sort()
+	})
+	if allocs != 0 {
+		t.Errorf("Sort() of %d pairs allocs = %v, want 0", len(pairs), allocs)
+	}
+}
+
+func TestFilters(t *testing.T) {
+	keys := []string{"vk_user_id", "sign", "hash", "vk_ts", "user"}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"All", All, keys},
+		{"Include", Include("user", "hash"), []string{"hash", "user"}},
+		{"Exclude", Exclude("sign", "hash"), []string{"vk_user_id", "vk_ts", "user"}},
+		{"Prefix", Prefix("vk_"), []string{"vk_user_id", "vk_ts"}},
+		{"And", And(Prefix("vk_"), Exclude("vk_ts")), []string{"vk_user_id"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, k := range keys {
+			if tt.filter(k) {
+				got = append(got, k)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSortAndWriters(t *testing.T) {
+	pairs := Pairs{{"b", "x y"}, {"a", "1"}, {"c", "&"}, {"a", "0"}}
+	pairs.Sort()
+	if want := (Pairs{{"a", "1"}, {"a", "0"}, {"b", "x y"}, {"c", "&"}}); !reflect.DeepEqual(pairs, want) {
+		t.Fatalf("Sort() = %q, want %q (stable)", pairs, want)
+	}
+
+	// Above the insertion sort threshold
+	long := make(Pairs, 0, 26)
+	for c := 'z'; c >= 'a'; c-- {
+		long = append(long, Pair{Key: string(c)})
+	}
+	long.Sort()
+	for i := 1; i < len(long); i++ {
+		if long[i-1].Key > long[i].Key {
+			t.Fatalf("Sort() of %d pairs is not sorted: %q", len(long), long)
+		}
+	}
+
+	// Merge sort against sort.Stable, with repeated keys
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{13, 24, 25, 100, 300} {
+		got := make(Pairs, n)
+		for i := range got {
+			got[i] = Pair{Key: strconv.Itoa(rnd.Intn(n / 3)), Value: strconv.Itoa(i)}
+		}
+		want := append(Pairs(nil), got...)
+		sort.Stable(want)
+		got.Sort()
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Sort() of %d pairs = %q, want %q (stable)", n, got, want)
+		}
+	}
+
+	tests := []struct {
+		name string
+		got  []byte
+		want string
+	}{
+		{"Lines", AppendLines(nil, pairs), "a=1\na=0\nb=x y\nc=&"},
+		{"Query", AppendQuery(nil, pairs), "a=1&a=0&b=x%20y&c=%26"},
+		{"Concat", AppendConcat(nil, pairs), "a=1a=0b=x yc=&"},
+		{"ConcatSecret", AppendConcatSecret(nil, pairs, "s3cret"), "a=1a=0b=x yc=&s3cret"},
+	}
+	for _, tt := range tests {
+		if string(tt.got) != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if v, ok := pairs.Get("b"); !ok || v != "x y" {
+		t.Errorf("Get(b) = %q, %v", v, ok)
+	}
+}
+
+func TestUnescape(t *testing.T) {
+	buf := make([]byte, 0, 4)
+	a, _ := Unescape("a%41", &buf)
+	b, _ := Unescape("b+c", &buf)
+	plain, _ := Unescape("plain", &buf)
+	if a != "aA" || b != "b c" || plain != "plain" {
+		t.Errorf("Unescape() = %q, %q, %q", a, b, plain)
+	}
+	for _, bad := range []string{"%", "%4", "%G1"} {
+		if _, ok := Unescape(bad, &buf); ok {
+			t.Errorf("Unescape(%q) ok, want false", bad)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package canon
+
+// Writer appends the canonical form of sorted pairs to dst.
+type Writer func(dst []byte, pairs Pairs) []byte
+
+// AppendLines writes "key=value" lines joined by '\n', unescaped.
+// This is the data check string of Telegram and MAX init data.
+func AppendLines(dst []byte, pairs Pairs) []byte {
+	for i, p := range pairs {
+		if i > 0 {
+			dst = append(dst, '\n')
+		}
+		dst = append(dst, p.Key...)
+		dst = append(dst, '=')
+		dst = append(dst, p.Value...)
+	}
+	return dst
+}
+
+// AppendQuery writes percent-encoded "key=value" pairs joined by '&'.
+// This is the signed string of VK Mini Apps launch parameters.
+func AppendQuery(dst []byte, pairs Pairs) []byte {
+	for i, p := range pairs {
+		if i > 0 {
+			dst = append(dst, '&')
+		}
+		dst = AppendEscape(dst, p.Key)
+		dst = append(dst, '=')
+		dst = AppendEscape(dst, p.Value)
+	}
+	return dst
+}
+
+// AppendConcat writes "key=value" pairs without separators, unescaped.
+func AppendConcat(dst []byte, pairs Pairs) []byte {
+	for _, p := range pairs {
+		dst = append(dst, p.Key...)
+		dst = append(dst, '=')
+		dst = append(dst, p.Value...)
+	}
+	return dst
+}
+
+// AppendConcatSecret writes the pairs like AppendConcat followed by
+// secret. This is the MD5 input of VK Shop and OK signatures.
+func AppendConcatSecret(dst []byte, pairs Pairs, secret string) []byte {
+	return append(AppendConcat(dst, pairs), secret...)
+}
@@ -0,0 +1,172 @@
+// Package deeplink provides signed deep link payloads for Telegram startapp
+// parameters and VK app link hashes.
+//
+// A payload carries a small value (typically campaign and referrer data)
+// together with a key ID, an optional expiry and a truncated HMAC-SHA256
+// tag, encoded with the [A-Za-z0-9_-] alphabet Telegram allows in start
+// parameters. Users can still read the value, but cannot change it.
+//
+// The platform launch data must be verified first (tma.Verify or
+// vkma.Verify); the payload is then decoded from the verified parameters.
+package deeplink
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+const (
+	// MaxLength is the longest payload accepted, matching the 512 character
+	// limit of Telegram start parameters.
+	MaxLength = 512
+
+	// version is the first byte of every payload.
+	version = 1
+
+	// macSize is the length of the truncated HMAC-SHA256 tag.
+	macSize = 10
+)
+
+// b64 encodes payloads with URL-safe base64 without padding.
+var b64 = base64.RawURLEncoding
+
+// now returns the current time; replaced in tests.
+var now = time.Now
+
+// Keys maps key IDs to signing secrets. Keeping old keys in the map while
+// signing with a new ID allows rotating secrets without breaking links
+// already shared.
+type Keys map[byte]string
+
+// Link is a decoded and verified payload.
+type Link struct {
+	// KeyID is the ID of the key the payload was signed with
+	KeyID byte
+
+	// Expires is the expiry time; zero means the link never expires
+	Expires time.Time
+
+	// Data is the signed value
+	Data []byte
+}
+
+// Encode signs data into a deep link payload.
+//
+// Parameters:
+//   - data: The value to sign
+//   - keyID: The ID of the signing key in keys
+//   - keys: The signing keys
+//   - expires: Expiry time; zero means no expiry
+//
+// Returns:
+//   - string: The payload, usable as startapp parameter or URL hash
+//   - bool: false if the key is unknown or the payload exceeds MaxLength
+func Encode(data []byte, keyID byte, keys Keys, expires time.Time) (string, bool) {
+	secret, ok := keys[keyID]
+	if !ok || secret == "" {
+		return "", false
+	}
+
+	var exp uint64
+	if !expires.IsZero() {
+		exp = uint64(expires.Unix())
+	}
+
+	b := make([]byte, 0, 2+binary.MaxVarintLen64+len(data)+macSize)
+	b = append(b, version, keyID)
+	b = binary.AppendUvarint(b, exp)
+	b = append(b, data...)
+
+	if b64.EncodedLen(len(b)+macSize) > MaxLength {
+		return "", false
+	}
+
+	mac := utils.GetHMAC(secret)
+	defer utils.PutHMAC(secret, mac)
+	mac.Write(b)
+
+	var sum [32]byte
+	b = append(b, mac.Sum(sum[:0])[:macSize]...)
+
+	return b64.EncodeToString(b), true
+}
+
+// Decode verifies a deep link payload.
+//
+// Parameters:
+//   - payload: The payload produced by Encode
+//   - keys: The keys the payload may be signed with
+//
+// Returns:
+//   - *Link: The decoded link if verification succeeds
+//   - bool: true if the payload is authentic, well-formed and not expired
+func Decode(payload string, keys Keys) (*Link, bool) {
+	if payload == "" || len(payload) > MaxLength || len(keys) == 0 {
+		return nil, false
+	}
+
+	var raw [MaxLength * 3 / 4]byte
+	n, err := b64.Decode(raw[:], []byte(payload))
+	if err != nil || n < 3+macSize {
+		return nil, false
+	}
+	b, tag := raw[:n-macSize], raw[n-macSize:n]
+
+	if b[0] != version {
+		return nil, false
+	}
+	secret, ok := keys[b[1]]
+	if !ok || secret == "" {
+		return nil, false
+	}
+
+	mac := utils.GetHMAC(secret)
+	defer utils.PutHMAC(secret, mac)
+	mac.Write(b)
+
+	var sum [32]byte
+	if !hmac.Equal(mac.Sum(sum[:0])[:macSize], tag) {
+		return nil, false
+	}
+
+	exp, k := binary.Uvarint(b[2:])
+	if k <= 0 {
+		return nil, false
+	}
+
+	link := &Link{KeyID: b[1], Data: append([]byte(nil), b[2+k:]...)}
+	if exp != 0 {
+		link.Expires = time.Unix(int64(exp), 0)
+		if !now().Before(link.Expires) {
+			return nil, false
+		}
+	}
+	return link, true
+}
+
+// Marshal encodes v as JSON and signs it with Encode.
+// Use short JSON field names to keep payloads compact.
+func Marshal(v any, keyID byte, keys Keys, expires time.Time) (string, bool) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	return Encode(data, keyID, keys, expires)
+}
+
+// Unmarshal verifies payload with Decode and decodes its JSON value into v.
+func Unmarshal(payload string, keys Keys, v any) (*Link, bool) {
+	link, ok := Decode(payload, keys)
+	if !ok {
+		return nil, false
+	}
+	if err := json.Unmarshal(link.Data, v); err != nil {
+		return nil, false
+	}
+	return link, true
+}
@@ -0,0 +1,25 @@
+package deeplink
+
+import (
+	"strings"
+
+	"github.com/elum-utils/sign/tma"
+)
+
+// FromTMA decodes the start_param of verified Telegram Mini App parameters
+// into v. p must come from tma.Verify.
+func FromTMA(p *tma.Params, keys Keys, v any) (*Link, bool) {
+	if p == nil {
+		return nil, false
+	}
+	return Unmarshal(p.StartParam, keys, v)
+}
+
+// FromVKHash decodes the hash part of a VK Mini App link (the text after
+// '#', with or without the '#') into v.
+//
+// The hash is not covered by the vkma launch signature; verify the launch
+// parameters with vkma.Verify first, then pass the hash the client reports.
+func FromVKHash(hash string, keys Keys, v any) (*Link, bool) {
+	return Unmarshal(strings.TrimPrefix(hash, "#"), keys, v)
+}
@@ -0,0 +1,106 @@
+package deeplink
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elum-utils/sign/tma"
+)
+
+var testKeys = Keys{1: "old-secret", 2: "new-secret"}
+
+type campaign struct {
+	Source   string `json:"s"`
+	Referrer int64  `json:"r"`
+}
+
+func TestEncodeDecode(t *testing.T) {
+	now = func() time.Time { return time.Unix(1700000000, 0) }
+	defer func() { now = time.Now }()
+
+	fresh, _ := Encode([]byte("promo"), 2, testKeys, time.Unix(1700000600, 0))
+	stale, _ := Encode([]byte("promo"), 2, testKeys, time.Unix(1699999999, 0))
+	forever, _ := Encode([]byte("promo"), 1, testKeys, time.Time{})
+
+	tests := []struct {
+		name      string
+		payload   string
+		keys      Keys
+		wantValid bool
+	}{
+		{"Empty payload", "", testKeys, false},
+		{"No keys", fresh, nil, false},
+		{"Bad alphabet", fresh[:len(fresh)-1] + "+", testKeys, false},
+		{"Too short", "AQI", testKeys, false},
+		{"Too long", strings.Repeat("A", MaxLength+1), testKeys, false},
+		{"Unknown key", fresh, Keys{1: "old-secret"}, false},
+		{"Wrong secret", fresh, Keys{2: "other"}, false},
+		{"Tampered", "B" + fresh[1:], testKeys, false},
+		{"Expired", stale, testKeys, false},
+		{"Valid with expiry", fresh, testKeys, true},
+		{"Valid rotated key", forever, testKeys, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, ok := Decode(tt.payload, tt.keys)
+			if ok != tt.wantValid {
+				t.Errorf("Decode() = %v, want %v", ok, tt.wantValid)
+			}
+			if ok && string(link.Data) != "promo" {
+				t.Errorf("Data = %q, want %q", link.Data, "promo")
+			}
+		})
+	}
+}
+
+func TestEncode_Limits(t *testing.T) {
+	if _, ok := Encode([]byte("x"), 3, testKeys, time.Time{}); ok {
+		t.Error("Encode() with unknown key = true, want false")
+	}
+	if _, ok := Encode(make([]byte, MaxLength), 1, testKeys, time.Time{}); ok {
+		t.Error("Encode() over MaxLength = true, want false")
+	}
+
+	s, ok := Encode(make([]byte, 360), 1, testKeys, time.Now().Add(time.Hour))
+	if !ok || len(s) > MaxLength {
+		t.Fatalf("Encode() = %d chars, %v", len(s), ok)
+	}
+	for _, c := range s {
+		if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			t.Fatalf("payload contains %q", c)
+		}
+	}
+}
+
+func TestFromTMA(t *testing.T) {
+	payload, ok := Marshal(campaign{Source: "ads", Referrer: 42}, 2, testKeys, time.Time{})
+	if !ok {
+		t.Fatal("Marshal() = false, want true")
+	}
+
+	var c campaign
+	link, ok := FromTMA(&tma.Params{StartParam: payload}, testKeys, &c)
+	if !ok {
+		t.Fatal("FromTMA() = false, want true")
+	}
+	if link.KeyID != 2 || c.Source != "ads" || c.Referrer != 42 {
+		t.Errorf("unexpected link %+v, value %+v", link, c)
+	}
+
+	if _, ok := FromVKHash("#"+payload, testKeys, &c); !ok {
+		t.Error("FromVKHash() = false, want true")
+	}
+	if _, ok := FromTMA(&tma.Params{StartParam: "plain"}, testKeys, &c); ok {
+		t.Error("FromTMA() with unsigned start_param = true, want false")
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	payload, _ := Encode([]byte(`{"s":"ads","r":42}`), 2, testKeys, time.Time{})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Decode(payload, testKeys)
+	}
+}
@@ -0,0 +1,99 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// pongResponse is the reply to a PING interaction.
+const pongResponse = `{"type":1}`
+
+// Handler is an http.Handler for a Discord interactions endpoint.
+//
+// It verifies every request, answers PING with PONG and passes all other
+// interactions to OnInteraction. Requests with an invalid signature get
+// 401 Unauthorized, as Discord requires.
+type Handler struct {
+	// PublicKey is the application public key.
+	PublicKey ed25519.PublicKey
+
+	// MaxAge limits the age of X-Signature-Timestamp; zero disables the check.
+	MaxAge time.Duration
+
+	// MaxBodySize limits the request body; zero means DefaultMaxBodySize.
+	MaxBodySize int64
+
+	// OnInteraction handles a verified, non-PING interaction and writes
+	// the interaction response.
+	OnInteraction func(w http.ResponseWriter, r *http.Request, i *Interaction)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, ok := verifyRequest(r, h.PublicKey, h.MaxAge, bodyLimit(h.MaxBodySize))
+	if !ok {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var i Interaction
+	if err := json.Unmarshal(body, &i); err != nil {
+		http.Error(w, "invalid interaction", http.StatusBadRequest)
+		return
+	}
+
+	if i.Type == Ping {
+		writePong(w)
+		return
+	}
+
+	if h.OnInteraction == nil {
+		http.Error(w, "unsupported interaction", http.StatusBadRequest)
+		return
+	}
+	h.OnInteraction(w, r, &i)
+}
+
+// Middleware returns HTTP middleware that verifies interaction requests
+// and answers PING itself. Verified non-PING requests reach next with the
+// body intact. maxBodySize limits the request body, like Handler.MaxBodySize;
+// zero means DefaultMaxBodySize.
+func Middleware(publicKey ed25519.PublicKey, maxAge time.Duration, maxBodySize int64) func(http.Handler) http.Handler {
+	maxBodySize = bodyLimit(maxBodySize)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, ok := verifyRequest(r, publicKey, maxAge, maxBodySize)
+			if !ok {
+				http.Error(w, "invalid request signature", http.StatusUnauthorized)
+				return
+			}
+
+			var probe struct {
+				Type InteractionType `json:"type"`
+			}
+			if json.Unmarshal(body, &probe) == nil && probe.Type == Ping {
+				writePong(w)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bodyLimit returns maxBodySize, or DefaultMaxBodySize if it is not positive.
+func bodyLimit(maxBodySize int64) int64 {
+	if maxBodySize <= 0 {
+		return DefaultMaxBodySize
+	}
+	return maxBodySize
+}
+
+// writePong writes the PONG interaction response.
+func writePong(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, pongResponse)
+}
@@ -0,0 +1,62 @@
+// Package discord provides types for Discord interactions, as delivered to
+// an interactions endpoint or used by Discord Activities.
+package discord
+
+import (
+	"encoding/json"
+)
+
+// InteractionType is the kind of interaction Discord delivers.
+type InteractionType int
+
+// Interaction types as defined by the Discord API.
+const (
+	Ping                           InteractionType = 1
+	ApplicationCommand             InteractionType = 2
+	MessageComponent               InteractionType = 3
+	ApplicationCommandAutocomplete InteractionType = 4
+	ModalSubmit                    InteractionType = 5
+)
+
+// User is a Discord user.
+type User struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name"`
+	Avatar     string `json:"avatar"`
+	Locale     string `json:"locale"`
+}
+
+// Member is a guild member. It carries the user for interactions in guilds.
+type Member struct {
+	User  *User    `json:"user"`
+	Nick  string   `json:"nick"`
+	Roles []string `json:"roles"`
+}
+
+// Interaction is an interaction payload.
+//
+// Discord documentation: https://discord.com/developers/docs/interactions/receiving-and-responding
+type Interaction struct {
+	ID            string          `json:"id"`
+	ApplicationID string          `json:"application_id"`
+	Type          InteractionType `json:"type"`
+	Data          json.RawMessage `json:"data"`
+	GuildID       string          `json:"guild_id"`
+	ChannelID     string          `json:"channel_id"`
+	Member        *Member         `json:"member"`
+	User          *User           `json:"user"`
+	Token         string          `json:"token"`
+	Version       int             `json:"version"`
+	Locale        string          `json:"locale"`
+	GuildLocale   string          `json:"guild_locale"`
+}
+
+// Invoker returns the user who triggered the interaction, taken from
+// Member in guilds and from User in direct messages.
+func (i *Interaction) Invoker() *User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	return i.User
+}
@@ -0,0 +1,117 @@
+// Package discord provides functionality for verifying Discord interaction
+// requests signed with Ed25519 over X-Signature-Timestamp plus the body.
+package discord
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// HeaderSignature carries the hex Ed25519 signature of the request.
+	HeaderSignature = "X-Signature-Ed25519"
+
+	// HeaderTimestamp carries the Unix timestamp the signature covers.
+	HeaderTimestamp = "X-Signature-Timestamp"
+
+	// DefaultMaxBodySize limits request bodies read by VerifyRequest.
+	DefaultMaxBodySize = 1 << 20
+)
+
+// now returns the current time; replaced in tests.
+var now = time.Now
+
+// ParsePublicKey decodes the hex application public key shown in the
+// Discord developer portal.
+func ParsePublicKey(hexKey string) (ed25519.PublicKey, bool) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, false
+	}
+	return ed25519.PublicKey(key), true
+}
+
+// Verify validates the signature of raw interaction data.
+//
+// Parameters:
+//   - publicKey: The application public key
+//   - signature: The hex value of X-Signature-Ed25519
+//   - timestamp: The value of X-Signature-Timestamp
+//   - body: The raw request body
+//   - maxAge: Maximum allowed difference between the timestamp and the
+//     current time; zero disables the freshness check
+//
+// Returns true if the Ed25519 signature of timestamp+body is valid.
+func Verify(publicKey ed25519.PublicKey, signature, timestamp string, body []byte, maxAge time.Duration) bool {
+	if len(publicKey) != ed25519.PublicKeySize || timestamp == "" {
+		return false
+	}
+	if len(signature) != ed25519.SignatureSize*2 {
+		return false
+	}
+
+	var sig [ed25519.SignatureSize]byte
+	if _, err := hex.Decode(sig[:], []byte(signature)); err != nil {
+		return false
+	}
+
+	if maxAge > 0 {
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return false
+		}
+		age := now().Sub(time.Unix(ts, 0))
+		if age > maxAge || age < -maxAge {
+			return false
+		}
+	}
+
+	msg := make([]byte, 0, len(timestamp)+len(body))
+	msg = append(msg, timestamp...)
+	msg = append(msg, body...)
+
+	return ed25519.Verify(publicKey, msg, sig[:])
+}
+
+// VerifyRequest reads and validates the body of an interaction request.
+//
+// Parameters:
+//   - r: The incoming request; its body is consumed
+//   - publicKey: The application public key
+//   - maxAge: See Verify
+//
+// Returns:
+//   - []byte: The request body if verification succeeds
+//   - bool: true if signature is valid, false otherwise
+//
+// Bodies larger than DefaultMaxBodySize are rejected.
+func VerifyRequest(r *http.Request, publicKey ed25519.PublicKey, maxAge time.Duration) ([]byte, bool) {
+	return verifyRequest(r, publicKey, maxAge, DefaultMaxBodySize)
+}
+
+// verifyRequest implements VerifyRequest with a configurable body limit.
+func verifyRequest(r *http.Request, publicKey ed25519.PublicKey, maxAge time.Duration, maxBodySize int64) ([]byte, bool) {
+	signature := r.Header.Get(HeaderSignature)
+	timestamp := r.Header.Get(HeaderTimestamp)
+	if signature == "" || timestamp == "" || r.Body == nil {
+		return nil, false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil || int64(len(body)) > maxBodySize {
+		return nil, false
+	}
+
+	if !Verify(publicKey, signature, timestamp, body, maxAge) {
+		return nil, false
+	}
+
+	// Let downstream handlers read the body again
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, true
+}
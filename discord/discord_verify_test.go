@@ -0,0 +1,184 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testKey = ed25519.NewKeyFromSeed([]byte("0123456789abcdef0123456789abcdef"))
+
+func signRequest(timestamp, body string) string {
+	return hex.EncodeToString(ed25519.Sign(testKey, []byte(timestamp+body)))
+}
+
+func newRequest(timestamp, signature, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(body))
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderSignature, signature)
+	return r
+}
+
+func TestParsePublicKey(t *testing.T) {
+	pub := testKey.Public().(ed25519.PublicKey)
+
+	if key, ok := ParsePublicKey(hex.EncodeToString(pub)); !ok || !key.Equal(pub) {
+		t.Error("ParsePublicKey() failed on a valid key")
+	}
+	if _, ok := ParsePublicKey("abcd"); ok {
+		t.Error("ParsePublicKey() accepted a short key")
+	}
+	if _, ok := ParsePublicKey(strings.Repeat("zz", 32)); ok {
+		t.Error("ParsePublicKey() accepted invalid hex")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now = func() time.Time { return time.Unix(1700000010, 0) }
+	defer func() { now = time.Now }()
+
+	pub := testKey.Public().(ed25519.PublicKey)
+	body := `{"type":1}`
+	sig := signRequest("1700000000", body)
+
+	tests := []struct {
+		name      string
+		publicKey ed25519.PublicKey
+		signature string
+		timestamp string
+		body      string
+		maxAge    time.Duration
+		wantValid bool
+	}{
+		{"Missing key", nil, sig, "1700000000", body, 0, false},
+		{"Missing timestamp", pub, sig, "", body, 0, false},
+		{"Short signature", pub, "abcd", "1700000000", body, 0, false},
+		{"Invalid hex", pub, strings.Repeat("zz", 64), "1700000000", body, 0, false},
+		{"Tampered body", pub, sig, "1700000000", `{"type":2}`, 0, false},
+		{"Tampered timestamp", pub, sig, "1700000001", body, 0, false},
+		{"Non-numeric timestamp", pub, signRequest("now", body), "now", body, time.Minute, false},
+		{"Stale", pub, sig, "1700000000", body, 5 * time.Second, false},
+		{"Valid", pub, sig, "1700000000", body, time.Minute, true},
+		{"Valid without freshness check", pub, sig, "1700000000", body, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ok := Verify(tt.publicKey, tt.signature, tt.timestamp, []byte(tt.body), tt.maxAge); ok != tt.wantValid {
+				t.Errorf("Verify() = %v, want %v", ok, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	pub := testKey.Public().(ed25519.PublicKey)
+	body := `{"type":2,"id":"1"}`
+
+	r := newRequest("1700000000", signRequest("1700000000", body), body)
+	got, ok := VerifyRequest(r, pub, 0)
+	if !ok || string(got) != body {
+		t.Fatalf("VerifyRequest() = %q, %v", got, ok)
+	}
+	again, _ := io.ReadAll(r.Body)
+	if string(again) != body {
+		t.Errorf("body not restored: %q", again)
+	}
+
+	r = newRequest("1700000000", "", body)
+	if _, ok := VerifyRequest(r, pub, 0); ok {
+		t.Error("VerifyRequest() accepted a request without signature")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	pub := testKey.Public().(ed25519.PublicKey)
+	var got *Interaction
+	h := &Handler{
+		PublicKey: pub,
+		OnInteraction: func(w http.ResponseWriter, r *http.Request, i *Interaction) {
+			got = i
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}
+
+	ping := `{"id":"1","type":1}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest("1700000000", signRequest("1700000000", ping), ping))
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"type":1}` {
+		t.Errorf("PING: status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+	if got != nil {
+		t.Error("PING must not reach OnInteraction")
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest("1700000000", signRequest("1700000000", ping), ping+" "))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("bad signature: status = %d, want 401", rec.Code)
+	}
+
+	command := `{"id":"2","type":2,"member":{"user":{"id":"42","username":"neo"}},"data":{"name":"play"}}`
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest("1700000000", signRequest("1700000000", command), command))
+	if rec.Code != http.StatusNoContent || got == nil {
+		t.Fatalf("command: status = %d, interaction = %v", rec.Code, got)
+	}
+	if got.Type != ApplicationCommand || got.Invoker().ID != "42" || string(got.Data) != `{"name":"play"}` {
+		t.Errorf("unexpected interaction: %+v", got)
+	}
+
+	h.MaxBodySize = 8
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest("1700000000", signRequest("1700000000", command), command))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("oversized body: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	pub := testKey.Public().(ed25519.PublicKey)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(b)
+	})
+	h := Middleware(pub, 0, 0)(next)
+
+	ping := `{"type":1}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest("1700000000", signRequest("1700000000", ping), ping))
+	if rec.Body.String() != `{"type":1}` {
+		t.Errorf("PING: body = %q", rec.Body.String())
+	}
+
+	command := `{"type":2}`
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest("1700000000", signRequest("1700000000", command), command))
+	if rec.Body.String() != command {
+		t.Errorf("command: body = %q, want %q", rec.Body.String(), command)
+	}
+
+	// The caller's body limit applies, not DefaultMaxBodySize
+	limited := Middleware(pub, 0, int64(len(command)-1))(next)
+	rec = httptest.NewRecorder()
+	limited.ServeHTTP(rec, newRequest("1700000000", signRequest("1700000000", command), command))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("oversized body: status = %d, want 401", rec.Code)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	pub := testKey.Public().(ed25519.PublicKey)
+	body := []byte(`{"id":"2","type":2,"data":{"name":"play"}}`)
+	sig := signRequest("1700000000", string(body))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Verify(pub, sig, "1700000000", body, 0)
+	}
+}
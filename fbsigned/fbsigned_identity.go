@@ -0,0 +1,40 @@
+package fbsigned
+
+import (
+	"time"
+
+	"github.com/elum-utils/sign"
+)
+
+// Scheme is the credential scheme of Facebook signed requests.
+const Scheme = sign.SchemeFacebook
+
+// Identity builds a platform-agnostic identity from a verified signed
+// request.
+func Identity(p *Params) *sign.Identity {
+	return &sign.Identity{
+		Platform: sign.Facebook,
+		UserID:   p.PlayerID,
+	}
+}
+
+// Verifier verifies signed requests and implements sign.Verifier.
+type Verifier struct {
+	// Secret is the app secret
+	Secret string
+
+	// MaxAge limits the age of issued_at; 0 disables the check
+	MaxAge time.Duration
+}
+
+// Scheme implements sign.Verifier.
+func (v *Verifier) Scheme() string { return Scheme }
+
+// Verify implements sign.Verifier.
+func (v *Verifier) Verify(raw string) (*sign.Identity, bool) {
+	p, ok := Verify(raw, v.Secret, v.MaxAge)
+	if !ok || p.PlayerID == "" {
+		return nil, false
+	}
+	return Identity(p), true
+}
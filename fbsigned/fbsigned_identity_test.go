@@ -0,0 +1,19 @@
+package fbsigned
+
+import (
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestVerifier(t *testing.T) {
+	id, ok := (&Verifier{Secret: testSecret}).Verify(testPlayer)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	want := sign.Identity{Platform: sign.Facebook, UserID: "1234567890123456"}
+	if *id != want {
+		t.Errorf("Identity = %+v, want %+v", *id, want)
+	}
+}
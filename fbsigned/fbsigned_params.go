@@ -0,0 +1,82 @@
+// Package fbsigned provides types for Facebook signed_request payloads,
+// as returned by Facebook Instant Games player and payments APIs.
+package fbsigned
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Params represents a decoded signed_request payload.
+//
+// Player fields are filled for FBInstant.player.getSignedPlayerInfoAsync,
+// purchase fields for the signedRequest of FBInstant.payments purchases.
+type Params struct {
+	// Algorithm is the signature algorithm, always "HMAC-SHA256"
+	Algorithm string
+
+	// IssuedAt is the time the payload was signed
+	IssuedAt time.Time
+
+	// PlayerID is the player's ID within the game
+	PlayerID string
+
+	// RequestPayload is the developer-provided value passed to the API call
+	RequestPayload string
+
+	// PaymentID is the payment identifier (purchases only)
+	PaymentID string
+
+	// ProductID is the purchased product identifier (purchases only)
+	ProductID string
+
+	// PurchaseTime is the time of the purchase (purchases only)
+	PurchaseTime time.Time
+
+	// PurchaseToken is the token used to consume the purchase (purchases only)
+	PurchaseToken string
+
+	// DeveloperPayload is the value passed to FBInstant.payments.purchaseAsync
+	DeveloperPayload string
+
+	// IsConsumed reports whether the purchase has been consumed (purchases only)
+	IsConsumed bool
+}
+
+// payload mirrors the JSON layout of a signed_request payload.
+type payload struct {
+	Algorithm        string `json:"algorithm"`
+	IssuedAt         int64  `json:"issued_at"`
+	PlayerID         string `json:"player_id"`
+	RequestPayload   string `json:"request_payload"`
+	PaymentID        string `json:"payment_id"`
+	ProductID        string `json:"product_id"`
+	PurchaseTime     int64  `json:"purchase_time"`
+	PurchaseToken    string `json:"purchase_token"`
+	DeveloperPayload string `json:"developer_payload"`
+	IsConsumed       bool   `json:"is_consumed"`
+}
+
+// decode parses a JSON payload into Params.
+func decode(data []byte) (*Params, bool) {
+	var p payload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, false
+	}
+
+	params := &Params{
+		Algorithm:        p.Algorithm,
+		IssuedAt:         time.Unix(p.IssuedAt, 0),
+		PlayerID:         p.PlayerID,
+		RequestPayload:   p.RequestPayload,
+		PaymentID:        p.PaymentID,
+		ProductID:        p.ProductID,
+		PurchaseToken:    p.PurchaseToken,
+		DeveloperPayload: p.DeveloperPayload,
+		IsConsumed:       p.IsConsumed,
+	}
+	if p.PurchaseTime != 0 {
+		params.PurchaseTime = time.Unix(p.PurchaseTime, 0)
+	}
+	return params, true
+}
@@ -0,0 +1,93 @@
+// Package fbsigned provides functionality for verifying Facebook signed_request
+// values, including those produced by Facebook Instant Games.
+package fbsigned
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// algorithm is the only signature algorithm accepted in signed requests.
+const algorithm = "HMAC-SHA256"
+
+// now returns the current time; replaced in tests.
+var now = time.Now
+
+// Verify validates a signed_request and decodes its payload.
+//
+// Parameters:
+//   - signedRequest: The "<signature>.<payload>" string, both parts base64url
+//   - secret: The application secret from the Facebook app dashboard
+//   - maxAge: Maximum allowed difference between issued_at and the current
+//     time; zero disables the freshness check
+//
+// Returns:
+//   - *Params: Decoded payload if verification succeeds
+//   - bool: true if signature is valid, false otherwise
+//
+// The verification process:
+//  1. Splits the request into signature and payload
+//  2. Computes HMAC-SHA256 of the encoded payload
+//  3. Compares it with the signature in constant time
+//  4. Decodes the payload and checks algorithm is HMAC-SHA256
+//  5. Checks issued_at freshness
+func Verify(signedRequest, secret string, maxAge time.Duration) (*Params, bool) {
+	// Early return for empty inputs
+	if secret == "" || signedRequest == "" {
+		return nil, false
+	}
+
+	dot := strings.IndexByte(signedRequest, '.')
+	if dot == -1 {
+		return nil, false
+	}
+	// Facebook omits padding, but tolerate it when present
+	sign := strings.TrimRight(signedRequest[:dot], "=")
+	data := signedRequest[dot+1:]
+	if len(sign) != base64.RawURLEncoding.EncodedLen(sha256.Size) || data == "" {
+		return nil, false
+	}
+
+	// Compute HMAC-SHA256 of the encoded payload
+	mac := utils.GetHMAC(secret)
+	defer utils.PutHMAC(secret, mac)
+	mac.Write([]byte(data))
+
+	var sum [sha256.Size]byte
+	mac.Sum(sum[:0])
+
+	var expected [43]byte // Unpadded base64url length of a SHA-256 sum
+	base64.RawURLEncoding.Encode(expected[:], sum[:])
+
+	// Constant-time comparison to prevent timing attacks
+	var diff byte
+	for i := 0; i < len(expected); i++ {
+		diff |= expected[i] ^ sign[i]
+	}
+	if diff != 0 {
+		return nil, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(data, "="))
+	if err != nil {
+		return nil, false
+	}
+
+	params, ok := decode(payload)
+	if !ok || params.Algorithm != algorithm {
+		return nil, false
+	}
+
+	if maxAge > 0 {
+		age := now().Sub(params.IssuedAt)
+		if age > maxAge || age < -maxAge {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
@@ -0,0 +1,76 @@
+package fbsigned
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	testSecret   = "fb-app-secret"
+	testPlayer   = "xabAmsl95wWxkpqB_yWB65M4dwZ4krXIBZ-05-x_HCQ.eyJhbGdvcml0aG0iOiJITUFDLVNIQTI1NiIsImlzc3VlZF9hdCI6MTcwMDAwMDAwMCwicGxheWVyX2lkIjoiMTIzNDU2Nzg5MDEyMzQ1NiIsInJlcXVlc3RfcGF5bG9hZCI6Im5vbmNlLTQyIn0"
+	testPurchase = "xsxN7H7nkqj72xBWWUkMYlqMd9kET-fpjocdRRsR-sc.eyJhbGdvcml0aG0iOiJITUFDLVNIQTI1NiIsImlzc3VlZF9hdCI6MTcwMDAwMDAwMCwicGxheWVyX2lkIjoiMTIzNDU2Nzg5MDEyMzQ1NiIsInJlcXVlc3RfcGF5bG9hZCI6IiIsInBheW1lbnRfaWQiOiI5ODc2IiwicHJvZHVjdF9pZCI6ImNvaW5zXzEwMCIsInB1cmNoYXNlX3RpbWUiOjE2OTk5OTk5OTAsInB1cmNoYXNlX3Rva2VuIjoidG9rLTEiLCJkZXZlbG9wZXJfcGF5bG9hZCI6Im9yZGVyLTciLCJpc19jb25zdW1lZCI6ZmFsc2V9"
+	testSHA1     = "3GwXnb_k2TwLAKQ5O2MtdpEXzr32K5VMigeTzOoaENE.eyJhbGdvcml0aG0iOiJITUFDLVNIQTEiLCJpc3N1ZWRfYXQiOjE3MDAwMDAwMDAsInBsYXllcl9pZCI6IjEifQ"
+)
+
+func TestVerify(t *testing.T) {
+	now = func() time.Time { return time.Unix(1700000060, 0) }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		name          string
+		signedRequest string
+		secret        string
+		maxAge        time.Duration
+		wantValid     bool
+	}{
+		{"Missing secret", testPlayer, "", 0, false},
+		{"Empty request", "", testSecret, 0, false},
+		{"No separator", "xabAmsl95wWxkpqB_yWB65M4dwZ4krXIBZ-05-x_HCQ", testSecret, 0, false},
+		{"Wrong secret", testPlayer, "other", 0, false},
+		{"Tampered payload", testPlayer + "x", testSecret, 0, false},
+		{"Unsupported algorithm", testSHA1, testSecret, 0, false},
+		{"Expired", testPlayer, testSecret, 30 * time.Second, false},
+		{"Valid fresh", testPlayer, testSecret, time.Minute, true},
+		{"Valid without freshness check", testPlayer, testSecret, 0, true},
+		{"Valid padded signature", testPlayer[:43] + "=" + testPlayer[43:], testSecret, 0, true},
+		{"Valid purchase", testPurchase, testSecret, time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := Verify(tt.signedRequest, tt.secret, tt.maxAge)
+			if ok != tt.wantValid {
+				t.Errorf("Verify() = %v, want %v", ok, tt.wantValid)
+			}
+			if ok && p == nil {
+				t.Error("Expected non-nil *Params on valid signature")
+			}
+		})
+	}
+}
+
+func TestVerify_Payload(t *testing.T) {
+	p, ok := Verify(testPlayer, testSecret, 0)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	if p.PlayerID != "1234567890123456" || p.RequestPayload != "nonce-42" || !p.IssuedAt.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected player payload: %+v", p)
+	}
+
+	p, ok = Verify(testPurchase, testSecret, 0)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	if p.PaymentID != "9876" || p.ProductID != "coins_100" || p.PurchaseToken != "tok-1" ||
+		p.DeveloperPayload != "order-7" || p.IsConsumed || !p.PurchaseTime.Equal(time.Unix(1699999990, 0)) {
+		t.Errorf("unexpected purchase payload: %+v", p)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Verify(testPlayer, testSecret, 0)
+	}
+}
@@ -0,0 +1,3 @@
+// Package race reports whether the race detector is enabled, so tests
+// counting allocations can skip: sync.Pool drops items at random under it.
+package race
@@ -1,20 +1,11 @@
 package utils
 
-// appendEscape performs percent-encoding (RFC 3986) on a string.
-// Writes directly into dst without allocating intermediate strings.
+import (
+	"github.com/elum-utils/sign/canon"
+)
+
+// AppendEscape performs percent-encoding (RFC 3986) on a string;
+// see canon.AppendEscape.
 func AppendEscape(dst []byte, s string) []byte {
-	for i := 0; i < len(s); i++ {
-		c := s[i]
-		if (c >= 'a' && c <= 'z') ||
-			(c >= 'A' && c <= 'Z') ||
-			(c >= '0' && c <= '9') ||
-			c == '-' || c == '_' || c == '.' || c == '~' {
-			dst = append(dst, c)
-		} else {
-			dst = append(dst, '%')
-			dst = append(dst, "0123456789ABCDEF"[c>>4])
-			dst = append(dst, "0123456789ABCDEF"[c&15])
-		}
-	}
-	return dst
+	return canon.AppendEscape(dst, s)
 }
@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// batchWorkers returns the number of goroutines for n items: workers if
+// positive, GOMAXPROCS otherwise, and never more than n.
+func batchWorkers(workers, n int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if n >= 0 && workers > n {
+		workers = n
+	}
+	return workers
+}
+
+// RunBatch calls fn for every index below n on at most workers goroutines
+// (GOMAXPROCS if workers <= 0) and returns when all calls are done.
+// Once ctx is done, the remaining indexes are passed to fn with ctx.Err().
+func RunBatch(ctx context.Context, n, workers int, fn func(i int, err error)) {
+	workers = batchWorkers(workers, n)
+	if workers == 0 {
+		return
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		next int
+	)
+	take := func() int {
+		mu.Lock()
+		i := next
+		next++
+		mu.Unlock()
+		return i
+	}
+
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := take(); i < n; i = take() {
+				fn(i, ctx.Err())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// RunStream reads in until it is closed, calls fn on at most workers
+// goroutines (GOMAXPROCS if workers <= 0) and sends the results to the
+// returned channel in input order. At most workers items are in flight.
+//
+// Once ctx is done no further input is read, results not yet delivered are
+// dropped and the returned channel is closed.
+func RunStream[In, Out any](ctx context.Context, in <-chan In, workers int, fn func(In) Out) <-chan Out {
+	workers = batchWorkers(workers, -1)
+	out := make(chan Out)
+
+	type job struct {
+		item In
+		res  chan Out
+	}
+	jobs := make(chan job)
+	order := make(chan chan Out, workers)
+
+	// Workers
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.res <- fn(j.item)
+			}
+		}()
+	}
+
+	// Dispatcher: order bounds the number of items in flight
+	go func() {
+		defer func() {
+			close(jobs)
+			close(order)
+		}()
+		for {
+			var item In
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok = <-in:
+				if !ok {
+					return
+				}
+			}
+
+			res := make(chan Out, 1)
+			select {
+			case <-ctx.Done():
+				return
+			case order <- res:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- job{item: item, res: res}:
+			}
+		}
+	}()
+
+	// Collector: delivers results in input order
+	go func() {
+		defer close(out)
+		defer wg.Wait()
+		for res := range order {
+			var r Out
+			select {
+			case <-ctx.Done():
+				return
+			case r = <-res:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- r:
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"unsafe"
+)
+
+// Detacher rebases strings produced by QueryUnescape so that they stay valid
+// after a pooled unescape buffer is returned to its pool.
+//
+// Strings pointing into the pooled backing array are moved to a single
+// owned copy of the decoded bytes; all other strings are returned unchanged.
+// When the buffer outgrew the pooled array, the grown array already holds
+// every decoded byte at the same offsets and nobody else owns it, so it is
+// reused instead of copying.
+type Detacher struct {
+	base  uintptr
+	size  uintptr
+	owned string
+}
+
+// NewDetacher prepares a Detacher for strings decoded into used, where
+// pooled is the buffer obtained from the pool before decoding started.
+// It allocates at most once, and only if pooled was written to.
+func NewDetacher(pooled, used []byte) Detacher {
+	if len(used) == 0 || cap(pooled) == 0 {
+		return Detacher{}
+	}
+
+	pooled = pooled[:cap(pooled)]
+	d := Detacher{
+		base: uintptr(unsafe.Pointer(&pooled[0])),
+		size: uintptr(len(pooled)),
+	}
+	if uintptr(unsafe.Pointer(&used[0])) == d.base {
+		d.owned = string(used) // Still the pooled array: copy out
+	} else {
+		d.owned = unsafe.String(&used[0], len(used)) // Grown array: take over
+	}
+	return d
+}
+
+// String returns s, rebased onto owned memory if it points into the pool.
+func (d *Detacher) String(s string) string {
+	if d.size == 0 || len(s) == 0 {
+		return s
+	}
+	p := uintptr(unsafe.Pointer(unsafe.StringData(s)))
+	if p < d.base || p >= d.base+d.size {
+		return s
+	}
+	off := int(p - d.base)
+	return d.owned[off : off+len(s)]
+}
+
+// BytesToString returns a string sharing memory with b, without copying.
+// The string is only valid while b is not modified.
+func BytesToString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(&b[0], len(b))
+}
+
+// CloneStrings moves the strings pointed to by ss into a single owned
+// allocation, so they no longer reference the memory they were read from.
+func CloneStrings(ss ...*string) {
+	n := 0
+	for _, s := range ss {
+		n += len(*s)
+	}
+	if n == 0 {
+		return
+	}
+
+	buf := make([]byte, 0, n)
+	for _, s := range ss {
+		buf = append(buf, *s...)
+	}
+	owned := unsafe.String(&buf[0], n)
+	for _, s := range ss {
+		l := len(*s)
+		*s, owned = owned[:l], owned[l:]
+	}
+}
@@ -0,0 +1,13 @@
+package utils
+
+import (
+	"github.com/elum-utils/sign"
+)
+
+// Limits returns l, or sign.DefaultLimits if l is nil.
+func Limits(l *sign.Limits) *sign.Limits {
+	if l == nil {
+		return &sign.DefaultLimits
+	}
+	return l
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+	"unsafe"
+)
+
+// LRU is a bounded cache of verification results keyed by raw input.
+//
+// Entries keep SHA-256 digests of the raw input and tag (the secret the
+// entry was verified with) instead of the strings themselves, so an entry
+// costs its value plus about 120 bytes, and secrets are not held in
+// plaintext. Entries are indexed by the first 64 bits of the raw digest; a
+// hit compares both full digests, so an index collision is a miss rather
+// than a wrong result. An LRU is safe for concurrent use.
+type LRU[V any] struct {
+	mu      sync.Mutex
+	size    int
+	items   map[uint64]*lruEntry[V]
+	root    lruEntry[V] // Sentinel: root.next is the most recently used
+	hits    uint64
+	misses  uint64
+	expired uint64
+}
+
+type lruEntry[V any] struct {
+	key        uint64
+	raw, tag   [sha256.Size]byte // Digests
+	expires    int64             // Unix nanoseconds
+	val        V
+	prev, next *lruEntry[V]
+}
+
+// NewLRU creates a cache holding at most size entries (at least one).
+func NewLRU[V any](size int) *LRU[V] {
+	if size < 1 {
+		size = 1
+	}
+	c := &LRU[V]{
+		size:  size,
+		items: make(map[uint64]*lruEntry[V], size),
+	}
+	c.root.prev, c.root.next = &c.root, &c.root
+	return c
+}
+
+// digest returns the SHA-256 digest of s without copying it.
+func digest(s string) [sha256.Size]byte {
+	return sha256.Sum256(unsafe.Slice(unsafe.StringData(s), len(s)))
+}
+
+// Get returns the value stored for raw and tag if it has not expired
+// at now (Unix nanoseconds).
+func (c *LRU[V]) Get(raw, tag string, now int64) (V, bool) {
+	rawSum, tagSum := digest(raw), digest(tag)
+	key := binary.LittleEndian.Uint64(rawSum[:8])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if ok && e.expires <= now {
+		c.remove(e)
+		c.expired++
+		ok = false
+	}
+	if !ok || e.raw != rawSum || e.tag != tagSum {
+		c.misses++
+		var zero V
+		return zero, false
+	}
+
+	c.hits++
+	c.unlink(e)
+	c.pushFront(e)
+	return e.val, true
+}
+
+// Add stores val for raw and tag until expires (Unix nanoseconds),
+// evicting the least recently used entry when the cache is full.
+func (c *LRU[V]) Add(raw, tag string, val V, expires int64) {
+	rawSum, tagSum := digest(raw), digest(tag)
+	key := binary.LittleEndian.Uint64(rawSum[:8])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+	if len(c.items) >= c.size {
+		c.remove(c.root.prev)
+	}
+
+	e := &lruEntry[V]{key: key, raw: rawSum, tag: tagSum, expires: expires, val: val}
+	c.items[key] = e
+	c.pushFront(e)
+}
+
+// Stats returns the hit, miss and expiry counters and the entry count.
+func (c *LRU[V]) Stats() (hits, misses, expired uint64, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses, c.expired, len(c.items)
+}
+
+// Purge removes all entries, keeping the counters.
+func (c *LRU[V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[uint64]*lruEntry[V], c.size)
+	c.root.prev, c.root.next = &c.root, &c.root
+}
+
+func (c *LRU[V]) remove(e *lruEntry[V]) {
+	c.unlink(e)
+	delete(c.items, e.key)
+}
+
+func (c *LRU[V]) unlink(e *lruEntry[V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+}
+
+func (c *LRU[V]) pushFront(e *lruEntry[V]) {
+	e.prev = &c.root
+	e.next = c.root.next
+	c.root.next.prev = e
+	c.root.next = e
+}
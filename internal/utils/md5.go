@@ -0,0 +1,22 @@
+package utils
+
+import "crypto/md5"
+
+// EqualMD5Hex reports whether sig is the hex encoding (either case) of sum.
+// It decodes sig on the fly, so no intermediate string is allocated.
+func EqualMD5Hex(sum [md5.Size]byte, sig string) bool {
+	if len(sig) != md5.Size*2 {
+		return false
+	}
+
+	var diff byte
+	for i := 0; i < md5.Size; i++ {
+		hi := FromHex(sig[i*2])
+		lo := FromHex(sig[i*2+1])
+		if hi == 255 || lo == 255 {
+			return false
+		}
+		diff |= sum[i] ^ (hi<<4 | lo)
+	}
+	return diff == 0
+}
@@ -5,6 +5,13 @@ import (
 	"sync"
 )
 
+// Capacity bounds for buffers returned to the pools. A buffer grown beyond
+// them by one large input is dropped instead of being kept for reuse.
+const (
+	maxPooledKV  = 256
+	maxPooledBuf = 64 << 10
+)
+
 var (
 	BufCanonicalPool = sync.Pool{
 		New: func() any {
@@ -41,3 +48,24 @@ var (
 		},
 	}
 )
+
+// PutKV returns a slice obtained from KVPool, keeping its growth in s
+// unless it exceeds maxPooledKV entries.
+func PutKV(p *KVSlice, s KVSlice) {
+	if cap(s) > maxPooledKV {
+		return
+	}
+	*p = s[:0]
+	KVPool.Put(p)
+}
+
+// PutBuf returns a buffer obtained from pool, keeping its growth in b
+// unless it exceeds maxPooledBuf bytes. It must not be used for buffers
+// whose bytes are still referenced by returned strings.
+func PutBuf(pool *sync.Pool, p *[]byte, b []byte) {
+	if cap(b) > maxPooledBuf {
+		return
+	}
+	*p = b[:0]
+	pool.Put(p)
+}
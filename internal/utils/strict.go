@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/elum-utils/sign"
+)
+
+// StrictScan walks the parameters of rawQuery for strict mode. It
+// unescapes every pair, rejects pairs without '=' and duplicate keys, and
+// passes the rest to check.
+//
+// An error returned by check, usually one of the sign parameter errors, is
+// wrapped into a *sign.ParamError holding a copy of the key and value.
+// A leading '?' is skipped. Input exceeding limits, or sign.DefaultLimits
+// if limits is nil, is rejected with sign.ErrTooLarge.
+func StrictScan(rawQuery string, limits *sign.Limits, check func(key, val string) error) error {
+	limits = Limits(limits)
+	if len(rawQuery) > 0 && rawQuery[0] == '?' {
+		rawQuery = rawQuery[1:]
+	}
+
+	if !limits.AllowsQuery(rawQuery) {
+		return sign.ErrTooLarge
+	}
+
+	pairsPtr := KVPool.Get().(*KVSlice)
+	pairs := (*pairsPtr)[:0]
+	defer func() { PutKV(pairsPtr, pairs) }()
+
+	// Decoded strings never outlive this call, so the grown buffer can be
+	// pooled as well
+	tmpBufPtr := TmpBufPool.Get().(*[]byte)
+	tmpBuf := (*tmpBufPtr)[:0]
+	defer func() { PutBuf(&TmpBufPool, tmpBufPtr, tmpBuf) }()
+
+	for len(rawQuery) > 0 {
+		pair := rawQuery
+		if i := strings.IndexByte(rawQuery, '&'); i >= 0 {
+			pair, rawQuery = rawQuery[:i], rawQuery[i+1:]
+		} else {
+			rawQuery = ""
+		}
+
+		rawKey, rawVal, ok := strings.Cut(pair, "=")
+		if !ok {
+			return &sign.ParamError{Key: strings.Clone(pair), Err: sign.ErrMalformedPair}
+		}
+		if !limits.AllowsPair(len(rawKey), len(rawVal)) {
+			return &sign.ParamError{Key: strings.Clone(rawKey), Err: sign.ErrTooLarge}
+		}
+		key, ok1 := QueryUnescape(rawKey, &tmpBuf)
+		val, ok2 := QueryUnescape(rawVal, &tmpBuf)
+		if !ok1 || !ok2 {
+			return &sign.ParamError{Key: strings.Clone(rawKey), Value: strings.Clone(rawVal), Err: sign.ErrMalformedPair}
+		}
+
+		for _, p := range pairs {
+			if p.Key == key {
+				return &sign.ParamError{Key: strings.Clone(key), Value: strings.Clone(val), Err: sign.ErrDuplicateKey}
+			}
+		}
+		pairs = append(pairs, KV{Key: key, Value: val})
+
+		if err := check(key, val); err != nil {
+			return &sign.ParamError{Key: strings.Clone(key), Value: strings.Clone(val), Err: err}
+		}
+	}
+	return nil
+}
+
+// IsDigits reports whether s is a non-empty string of ASCII digits,
+// optionally preceded by '-' when signed is true.
+func IsDigits(s string, signed bool) bool {
+	if signed && len(s) > 1 && s[0] == '-' {
+		s = s[1:]
+	}
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
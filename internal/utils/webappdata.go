@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/canon"
+)
+
+var (
+	// webAppDataKeys caches derived WebAppData keys per bot token.
+	webAppDataKeys = sync.Map{}
+)
+
+// WebAppDataKey derives the secret key used to sign Telegram-style init data:
+// HMAC-SHA256 of the bot token keyed with "WebAppData".
+// Derived keys are cached per token.
+func WebAppDataKey(token string) []byte {
+	if key, ok := webAppDataKeys.Load(token); ok {
+		return key.([]byte)
+	}
+	h := hmac.New(sha256.New, []byte("WebAppData"))
+	h.Write([]byte(token))
+	key := h.Sum(nil)
+	webAppDataKeys.Store(token, key)
+	return key
+}
+
+// VerifyWebAppData validates Telegram-style init data against secretKey.
+//
+// The data check string is built from all parameters except hash, sorted by
+// key and joined as "key=value" lines; its HMAC-SHA256 must match the hex hash.
+// set is called for every signed parameter once the signature is valid;
+// the values it receives stay valid after the call returns. A nil limits
+// means sign.DefaultLimits.
+//
+// Returns false for malformed queries (including parameters without '='),
+// a missing or malformed hash, or a signature mismatch.
+func VerifyWebAppData(rawQuery string, secretKey []byte, limits *sign.Limits, set func(key, val string)) bool {
+	var hash string
+
+	// Get key-value pairs from pool to avoid allocations
+	pairsPtr := KVPool.Get().(*KVSlice)
+	pairs := (*pairsPtr)[:0] // Slice reset without reallocation
+	defer func() { PutKV(pairsPtr, pairs) }()
+
+	// Get temporary buffer from pool for unescaping
+	tmpBufPtr := TmpBufPool.Get().(*[]byte)
+	tmpBuf := (*tmpBufPtr)[:0]
+	defer TmpBufPool.Put(tmpBufPtr)
+
+	// Parse query string; parameters without '=' are malformed
+	it := canon.NewIterator(rawQuery, tmpBuf, canon.Options{RejectBare: true, Limits: limits})
+	for p, ok := it.Next(); ok; p, ok = it.Next() {
+		// Separate hash parameter from others
+		if p.Key == "hash" {
+			hash = p.Value
+		} else {
+			pairs = append(pairs, p)
+		}
+	}
+	if it.Err() != nil {
+		return false
+	}
+	tmpBuf = it.Buffer()
+
+	// Hash parameter is mandatory
+	if hash == "" {
+		return false
+	}
+
+	// Sort parameters lexicographically by key
+	pairs.Sort()
+
+	// Build canonical string for signing
+	bufPtr := BufCanonicalPool.Get().(*[]byte)
+	buf := (*bufPtr)[:0]
+	defer func() { PutBuf(&BufCanonicalPool, bufPtr, buf) }()
+
+	buf = canon.AppendLines(buf, pairs)
+
+	// Compute HMAC-SHA256 signature
+	mac := GetHMACBytes(secretKey)
+	defer PutHMACBytes(secretKey, mac)
+	mac.Write(buf)
+
+	// Get buffer for computed hash from pool
+	sumPtr := Sha256SumBufPool.Get().(*[]byte)
+	sum := (*sumPtr)[:sha256.Size]
+	defer Sha256SumBufPool.Put(sumPtr)
+	computedHash := mac.Sum(sum[:0]) // Reuses the sum buffer
+
+	// Decode provided hex hash
+	decodedPtr := Sha256SumBufPool.Get().(*[]byte)
+	decodedHash := (*decodedPtr)[:sha256.Size]
+	defer Sha256SumBufPool.Put(decodedPtr)
+
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	if _, err := DecodeHexStringInto(hash, decodedHash); err != nil {
+		return false // Invalid hex encoding
+	}
+
+	// Constant-time comparison to prevent timing attacks
+	if !hmac.Equal(computedHash, decodedHash) {
+		return false
+	}
+
+	// Store parameters, detached from the pooled unescape buffer
+	d := NewDetacher(*tmpBufPtr, tmpBuf)
+	for _, p := range pairs {
+		set(d.String(p.Key), d.String(p.Value))
+	}
+	return true
+}
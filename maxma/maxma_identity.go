@@ -0,0 +1,40 @@
+package maxma
+
+import (
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/tma"
+)
+
+// Scheme is the credential scheme of MAX mini app init data.
+const Scheme = sign.SchemeMAX
+
+// Identity builds a platform-agnostic identity from verified parameters
+// and the decoded user. The launch source is the start parameter.
+func Identity(p *Params, u *User) *sign.Identity {
+	id := tma.Identity(&tma.Params{StartParam: p.StartParam}, u)
+	id.Platform = sign.MAX
+	return id
+}
+
+// Verifier verifies init data with a bot token and implements
+// sign.Verifier. Init data without a user is rejected.
+type Verifier struct {
+	// Token is the bot token
+	Token string
+}
+
+// Scheme implements sign.Verifier.
+func (v *Verifier) Scheme() string { return Scheme }
+
+// Verify implements sign.Verifier.
+func (v *Verifier) Verify(raw string) (*sign.Identity, bool) {
+	p, ok := Verify(raw, v.Token)
+	if !ok || p.UserData == "" {
+		return nil, false
+	}
+	u, err := p.User()
+	if err != nil {
+		return nil, false
+	}
+	return Identity(p, u), true
+}
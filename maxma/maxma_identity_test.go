@@ -0,0 +1,27 @@
+package maxma
+
+import (
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestVerifier(t *testing.T) {
+	id, ok := (&Verifier{Token: testToken}).Verify(testQuery)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	want := sign.Identity{
+		Platform:  sign.MAX,
+		UserID:    "400123",
+		Name:      "Анна",
+		Username:  "anna",
+		Language:  "ru",
+		AvatarURL: "https://i.oneme.ru/a.jpg",
+		Source:    "ref_42",
+	}
+	if *id != want {
+		t.Errorf("Identity = %+v, want %+v", *id, want)
+	}
+}
@@ -0,0 +1,81 @@
+// Package maxma provides functionality for handling MAX messenger mini app
+// parameters. MAX passes Telegram-style init data, so user payloads share
+// the types of the tma package.
+package maxma
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/elum-utils/sign/tma"
+)
+
+// User represents a MAX user. MAX uses the same JSON schema as Telegram
+// (id, first_name, last_name, username, language_code, photo_url).
+type User = tma.User
+
+// Chat represents the chat the mini app was opened from. MAX sends the id
+// and type (e.g. "DIALOG", "CHAT") of the Telegram chat schema.
+type Chat = tma.Chat
+
+// Params represents the init data received from a MAX mini app.
+type Params struct {
+	// QueryID identifies the mini app session for answering queries
+	QueryID string `json:"query_id" msgpack:"query_id"`
+
+	// UserData contains the serialized user information in JSON format.
+	// Use the User() method to access structured data.
+	UserData string `json:"user" msgpack:"user"`
+
+	// ChatData contains the serialized chat information in JSON format.
+	// Use the Chat() method to access structured data.
+	ChatData string `json:"chat" msgpack:"chat"`
+
+	// StartParam is the value of the startapp parameter of the launch link
+	StartParam string `json:"start_param" msgpack:"start_param"`
+
+	// AuthDate represents the timestamp when the data was signed
+	AuthDate time.Time `json:"auth_date" msgpack:"auth_date"`
+
+	// Hash is the verification hash of the init data
+	Hash string `json:"hash" msgpack:"hash"`
+}
+
+// User parses the UserData field using the tma decoder and returns the user.
+func (p *Params) User() (*User, error) {
+	var user User
+	err := tma.DecodeUser(p.UserData, &user)
+	return &user, err
+}
+
+// Chat parses the ChatData field using the tma decoder and returns the chat.
+func (p *Params) Chat() (*Chat, error) {
+	var chat Chat
+	err := tma.DecodeChat(p.ChatData, &chat)
+	return &chat, err
+}
+
+// set updates the specified field in the Params struct based on the provided key.
+//
+// Parameters:
+//   - key: The field name to set (must match exactly)
+//   - value: The string value to set/parse
+//
+// Note: This method silently ignores unsupported keys and parsing errors.
+func (p *Params) set(key string, value string) {
+	switch key {
+	case "query_id":
+		p.QueryID = value
+	case "user":
+		p.UserData = value
+	case "chat":
+		p.ChatData = value
+	case "start_param":
+		p.StartParam = value
+	case "auth_date":
+		// Attempt to parse the value as a Unix timestamp
+		if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+			p.AuthDate = time.Unix(timestamp, 0)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Package maxma provides functionality for verifying MAX messenger mini app
+// init data. The signature scheme is the one used by Telegram Mini Apps:
+// HMAC-SHA256 over the sorted, newline-joined parameters, keyed with
+// HMAC-SHA256("WebAppData", bot token).
+package maxma
+
+import (
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// Verify validates MAX mini app init data against the bot token.
+//
+// Parameters:
+//   - rawQuery: The URL-encoded init data received from MAX
+//   - token: The bot token of the mini app
+//
+// Returns:
+//   - *Params: Parsed parameters if verification succeeds
+//   - bool: Verification result (true if valid)
+//
+// Parsing and canonicalization are shared with tma.Verify.
+func Verify(rawQuery, token string) (*Params, bool) {
+	// Early return for empty inputs
+	if token == "" || rawQuery == "" {
+		return nil, false
+	}
+
+	var params Params
+	if !utils.VerifyWebAppData(rawQuery, utils.WebAppDataKey(token), nil, params.set) {
+		return nil, false
+	}
+
+	// Move to the heap only on success, keeping failures allocation-free
+	result := new(Params)
+	*result = params
+	return result, true
+}
@@ -0,0 +1,90 @@
+package maxma
+
+import (
+	"testing"
+)
+
+const (
+	testToken = "max-bot-token:ABCDEF"
+	testQuery = "query_id=AAH-abc&user=%7B%22id%22%3A400123%2C%22first_name%22%3A%22%D0%90%D0%BD%D0%BD%D0%B0%22%2C%22last_name%22%3A%22%22%2C%22username%22%3A%22anna%22%2C%22language_code%22%3A%22ru%22%2C%22photo_url%22%3A%22https%3A%2F%2Fi.oneme.ru%2Fa.jpg%22%7D&chat=%7B%22id%22%3A-70123%2C%22type%22%3A%22CHAT%22%7D&start_param=ref_42&auth_date=1733485316&hash=d5f2dfc14195fe607896e375f6bc15c9e6800392d9ed160c955eee628c79f2bd"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rawQuery  string
+		token     string
+		wantValid bool
+	}{
+		{"Missing token", testQuery, "", false},
+		{"Empty query", "", testToken, false},
+		{"Wrong token", testQuery, "other-token", false},
+		{"Missing hash", testQuery[:len(testQuery)-70], testToken, false},
+		{"Invalid hash", testQuery[:len(testQuery)-64] + "zz", testToken, false},
+		{"Tampered start_param", "start_param=ref_43&" + testQuery, testToken, false},
+		{"Malformed params", testQuery + "&%gh", testToken, false},
+		{"Valid params", testQuery, testToken, true},
+		{"Valid with question mark", "?" + testQuery, testToken, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := Verify(tt.rawQuery, tt.token)
+			if ok != tt.wantValid {
+				t.Errorf("Verify() = %v, want %v", ok, tt.wantValid)
+			}
+			if ok && p == nil {
+				t.Error("Expected non-nil *Params on valid signature")
+			}
+		})
+	}
+}
+
+func TestVerify_Params(t *testing.T) {
+	p, ok := Verify(testQuery, testToken)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	// Values decoded from pooled buffers must survive later verifications
+	for i := 0; i < 10; i++ {
+		_, _ = Verify(testQuery, testToken)
+	}
+
+	if p.QueryID != "AAH-abc" || p.StartParam != "ref_42" || p.AuthDate.Unix() != 1733485316 {
+		t.Errorf("unexpected params: %+v", p)
+	}
+
+	user, err := p.User()
+	if err != nil {
+		t.Fatalf("User() error = %v", err)
+	}
+	if user.ID != 400123 || user.FirstName != "Анна" || user.UserName != "anna" || user.PhotoURL != "https://i.oneme.ru/a.jpg" {
+		t.Errorf("unexpected user: %+v", user)
+	}
+
+	chat, err := p.Chat()
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if chat.ID != -70123 || chat.Type != "CHAT" {
+		t.Errorf("unexpected chat: %+v", chat)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	b.Run("Valid params", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = Verify(testQuery, testToken)
+		}
+	})
+	b.Run("Valid params (parallel)", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_, _ = Verify(testQuery, testToken)
+			}
+		})
+	})
+}
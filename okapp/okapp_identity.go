@@ -0,0 +1,39 @@
+package okapp
+
+import (
+	"strconv"
+
+	"github.com/elum-utils/sign"
+)
+
+// Scheme is the credential scheme of OK launch parameters.
+const Scheme = sign.SchemeOK
+
+// Identity builds a platform-agnostic identity from verified launch
+// parameters. The launch source is refplace.
+func Identity(p *Params) *sign.Identity {
+	return &sign.Identity{
+		Platform: sign.OK,
+		UserID:   strconv.FormatInt(p.LoggedUserID, 10),
+		Source:   p.RefPlace,
+	}
+}
+
+// Verifier verifies launch parameters and implements sign.Verifier.
+// Launch parameters without a logged in user are rejected.
+type Verifier struct {
+	// Secrets maps application keys to their secret keys
+	Secrets map[string]string
+}
+
+// Scheme implements sign.Verifier.
+func (v *Verifier) Scheme() string { return Scheme }
+
+// Verify implements sign.Verifier.
+func (v *Verifier) Verify(raw string) (*sign.Identity, bool) {
+	p, ok := Verify(raw, v.Secrets)
+	if !ok || p.LoggedUserID == 0 {
+		return nil, false
+	}
+	return Identity(p), true
+}
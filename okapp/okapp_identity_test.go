@@ -0,0 +1,21 @@
+package okapp
+
+import (
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestVerifier(t *testing.T) {
+	v := &Verifier{Secrets: map[string]string{"CBAFGHJKLMNOPQRST": "SECRETKEY123"}}
+
+	id, ok := v.Verify(testQuery + "&sig=eb0495412c7f1b55feb8161cd9c34499")
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	want := sign.Identity{Platform: sign.OK, UserID: "575426848451", Source: "user_apps"}
+	if *id != want {
+		t.Errorf("Identity = %+v, want %+v", *id, want)
+	}
+}
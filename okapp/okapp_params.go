@@ -0,0 +1,80 @@
+// Package okapp provides types and functionality for handling Odnoklassniki (OK)
+// application launch parameters passed to apps opened inside OK.
+package okapp
+
+import (
+	"strconv"
+)
+
+// Params contains the launch parameters OK appends to the application URL.
+// The struct tags match the query parameter names used by OK.
+//
+// OK Apps documentation: https://apiok.ru/en/dev/app/create
+type Params struct {
+	ApiServer        string `schema:"api_server"`         // Base URL for REST API calls
+	ApiConnection    string `schema:"apiconnection"`      // Connection name for the JS API bridge
+	ApplicationKey   string `schema:"application_key"`    // Public application key
+	AuthSig          string `schema:"auth_sig"`           // md5(logged_user_id + session_key + secret)
+	Authorized       bool   `schema:"authorized"`         // Is the user authorized in the app
+	Container        string `schema:"container"`          // Launch container (e.g. "popup")
+	CustomArgs       string `schema:"custom_args"`        // Arguments passed by a deep link
+	FirstStart       bool   `schema:"first_start"`        // Is this the first launch by the user
+	IPGeoLocation    string `schema:"ip_geo_location"`    // Country and city resolved from IP
+	LoggedUserID     int64  `schema:"logged_user_id"`     // ID of the current OK user
+	Mob              bool   `schema:"mob"`                // Launched from the mobile version
+	RefPlace         string `schema:"refplace"`           // Place in OK the app was launched from
+	Referer          string `schema:"referer"`            // ID of the user who invited the current one
+	SessionKey       string `schema:"session_key"`        // Session key for REST API calls
+	SessionSecretKey string `schema:"session_secret_key"` // Session secret for client-side signing
+	WebServer        string `schema:"web_server"`         // OK web server the app was opened on
+	Sig              string `schema:"sig"`                // Security signature
+}
+
+// set assigns a value to the appropriate field in Params based on the key.
+//
+// Parameters:
+//   - key: The parameter name (must match schema tags exactly)
+//   - value: The string value to be parsed and assigned
+//
+// The method silently ignores unsupported keys and parsing errors,
+// leaving fields at their zero values when parsing fails.
+func (p *Params) set(key string, value string) {
+	switch key {
+	case "api_server":
+		p.ApiServer = value
+	case "apiconnection":
+		p.ApiConnection = value
+	case "application_key":
+		p.ApplicationKey = value
+	case "auth_sig":
+		p.AuthSig = value
+	case "authorized":
+		p.Authorized = value == "1"
+	case "container":
+		p.Container = value
+	case "custom_args":
+		p.CustomArgs = value
+	case "first_start":
+		p.FirstStart = value == "1"
+	case "ip_geo_location":
+		p.IPGeoLocation = value
+	case "logged_user_id":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			p.LoggedUserID = v
+		}
+	case "mob":
+		p.Mob = value == "true" || value == "1"
+	case "refplace":
+		p.RefPlace = value
+	case "referer":
+		p.Referer = value
+	case "session_key":
+		p.SessionKey = value
+	case "session_secret_key":
+		p.SessionSecretKey = value
+	case "web_server":
+		p.WebServer = value
+	case "sig":
+		p.Sig = value
+	}
+}
@@ -0,0 +1,82 @@
+package okapp
+
+import (
+	"testing"
+)
+
+func TestParams_set(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		expected func(*Params) bool
+	}{
+		{
+			name:  "logged_user_id sets int64",
+			key:   "logged_user_id",
+			value: "575426848451",
+			expected: func(p *Params) bool {
+				return p.LoggedUserID == 575426848451
+			},
+		},
+		{
+			name:  "logged_user_id with invalid format doesn't set",
+			key:   "logged_user_id",
+			value: "abc",
+			expected: func(p *Params) bool {
+				return p.LoggedUserID == 0
+			},
+		},
+		{
+			name:  "authorized sets true",
+			key:   "authorized",
+			value: "1",
+			expected: func(p *Params) bool {
+				return p.Authorized
+			},
+		},
+		{
+			name:  "mob accepts true",
+			key:   "mob",
+			value: "true",
+			expected: func(p *Params) bool {
+				return p.Mob
+			},
+		},
+		{
+			name:  "application_key sets string",
+			key:   "application_key",
+			value: "CBAFGHJKLMNOPQRST",
+			expected: func(p *Params) bool {
+				return p.ApplicationKey == "CBAFGHJKLMNOPQRST"
+			},
+		},
+		{
+			name:  "session_secret_key sets string",
+			key:   "session_secret_key",
+			value: "abc",
+			expected: func(p *Params) bool {
+				return p.SessionSecretKey == "abc"
+			},
+		},
+		{
+			name:  "unknown key doesn't modify Params",
+			key:   "unknown",
+			value: "value",
+			expected: func(p *Params) bool {
+				return *p == Params{}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Params
+			p.set(tt.key, tt.value)
+
+			if !tt.expected(&p) {
+				t.Errorf("set(%s, %s) failed: %+v", tt.key, tt.value, p)
+			}
+		})
+	}
+}
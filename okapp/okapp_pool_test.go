@@ -0,0 +1,45 @@
+package okapp
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// signQuery appends the OK signature of rawQuery made with secret.
+func signQuery(rawQuery, secret string) string {
+	q, _ := url.ParseQuery(rawQuery)
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + "=" + q.Get(k))
+	}
+	b.WriteString(secret)
+	sum := md5.Sum([]byte(b.String()))
+	return rawQuery + "&sig=" + hex.EncodeToString(sum[:])
+}
+
+func TestVerify_PooledBuffers(t *testing.T) {
+	secrets := map[string]string{"CBAFGHJKLMNOPQRST": "SECRETKEY123"}
+
+	first, ok := Verify(signQuery("application_key=CBAFGHJKLMNOPQRST&custom_args=ref+one", "SECRETKEY123"), secrets)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	// A second verification reuses the pooled unescape buffer
+	if _, ok := Verify(signQuery("application_key=CBAFGHJKLMNOPQRST&custom_args=ZZZ+ZZZ", "SECRETKEY123"), secrets); !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	if first.CustomArgs != "ref one" {
+		t.Errorf("CustomArgs after reuse = %q, want %q", first.CustomArgs, "ref one")
+	}
+}
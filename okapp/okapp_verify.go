@@ -0,0 +1,146 @@
+// Package okapp provides functionality for verifying Odnoklassniki (OK)
+// application launch parameters using OK's MD5-based signatures.
+package okapp
+
+import (
+	"crypto/md5"
+
+	"github.com/elum-utils/sign/canon"
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// Verify validates the signature of OK application launch parameters against
+// the application secret keys.
+//
+// Parameters:
+//   - rawQuery: The raw URL query string containing launch parameters
+//   - secrets: A map of application keys (application_key) to their secret keys
+//
+// Returns:
+//   - *Params: Parsed launch parameters if verification succeeds
+//   - bool: true if signature is valid, false otherwise
+//
+// The verification process:
+//  1. Parses and validates required parameters (application_key and sig)
+//  2. Selects the appropriate secret based on application_key
+//  3. Concatenates all key=value pairs except sig, sorted by key, plus the secret
+//  4. Compares the MD5 hash with sig
+//  5. If auth_sig is present, checks it equals md5(logged_user_id + session_key + secret)
+func Verify(rawQuery string, secrets map[string]string) (*Params, bool) {
+	// Early return if no secrets provided
+	if len(secrets) == 0 {
+		return nil, false
+	}
+	return verify(rawQuery, secrets, "")
+}
+
+// VerifySession validates parameters signed with a session secret key instead
+// of the application secret key. OK uses this scheme for requests signed on the
+// client side, where the application secret must not be exposed.
+//
+// Parameters:
+//   - rawQuery: The raw URL query string containing signed parameters
+//   - sessionSecretKey: The session_secret_key issued to the current session
+//
+// Returns:
+//   - *Params: Parsed parameters if verification succeeds
+//   - bool: true if signature is valid, false otherwise
+//
+// auth_sig is not checked, since it is always signed with the application secret.
+func VerifySession(rawQuery, sessionSecretKey string) (*Params, bool) {
+	if sessionSecretKey == "" {
+		return nil, false
+	}
+	return verify(rawQuery, nil, sessionSecretKey)
+}
+
+// verify implements Verify and VerifySession. When secrets is nil,
+// sessionSecret is used to check sig and auth_sig is ignored.
+func verify(rawQuery string, secrets map[string]string, sessionSecret string) (*Params, bool) {
+	var appKey, sig, authSig, userID, sessionKey string
+
+	// Get key-value pairs from sync.Pool to reduce allocations
+	pairsPtr := utils.KVPool.Get().(*utils.KVSlice)
+	pairs := (*pairsPtr)[:0] // Slice reset without reallocation
+	defer func() { utils.PutKV(pairsPtr, pairs) }()
+
+	// Get temporary buffer for URL unescaping from pool
+	tmpBufPtr := utils.TmpBufPool.Get().(*[]byte)
+	defer utils.TmpBufPool.Put(tmpBufPtr)
+
+	// Parse query string parameters, skipping those without values
+	it := canon.NewIterator(rawQuery, (*tmpBufPtr)[:0], canon.Options{})
+	for p, ok := it.Next(); ok; p, ok = it.Next() {
+		// Categorize parameters
+		switch p.Key {
+		case "sig":
+			sig = p.Value // Store signature separately
+			continue
+		case "application_key":
+			appKey = p.Value
+		case "auth_sig":
+			authSig = p.Value
+		case "logged_user_id":
+			userID = p.Value
+		case "session_key":
+			sessionKey = p.Value
+		}
+		pairs = append(pairs, p)
+	}
+	if it.Err() != nil {
+		return nil, false // Oversized input or invalid escapes
+	}
+
+	// Verify required parameters exist
+	if sig == "" {
+		return nil, false
+	}
+
+	secret := sessionSecret
+	if secrets != nil {
+		var ok bool
+		if appKey == "" {
+			return nil, false
+		}
+		if secret, ok = secrets[appKey]; !ok {
+			return nil, false // Unknown application key
+		}
+	}
+
+	// Sort parameters lexicographically by key
+	pairs.Sort()
+
+	// Get buffer for signature string from pool
+	bufPtr := utils.BufCanonicalPool.Get().(*[]byte)
+	buf := (*bufPtr)[:0]
+	defer func() { utils.PutBuf(&utils.BufCanonicalPool, bufPtr, buf) }()
+
+	// Detach stored values from the pooled unescape buffer
+	d := utils.NewDetacher(*tmpBufPtr, it.Buffer())
+
+	params := &Params{} // Only allocation for result
+	for _, p := range pairs {
+		params.set(p.Key, d.String(p.Value))
+	}
+	params.Sig = d.String(sig)
+
+	// Signature string format: key=value pairs without separators,
+	// followed by the secret
+	buf = canon.AppendConcatSecret(buf, pairs, secret)
+
+	if !utils.EqualMD5Hex(md5.Sum(buf), sig) {
+		return nil, false
+	}
+
+	// auth_sig binds the user to the session with the application secret
+	if secrets != nil && authSig != "" {
+		buf = append(buf[:0], userID...)
+		buf = append(buf, sessionKey...)
+		buf = append(buf, secret...)
+		if !utils.EqualMD5Hex(md5.Sum(buf), authSig) {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
@@ -0,0 +1,164 @@
+package okapp
+
+import (
+	"strings"
+	"testing"
+)
+
+const testQuery = "api_server=https%3A%2F%2Fapi.ok.ru%2F" +
+	"&apiconnection=512000_1700000000000" +
+	"&application_key=CBAFGHJKLMNOPQRST" +
+	"&auth_sig=e7562d245a873267132f8a1bb33c122b" +
+	"&authorized=1" +
+	"&custom_args=ref%3Dad+1" +
+	"&first_start=0" +
+	"&logged_user_id=575426848451" +
+	"&refplace=user_apps" +
+	"&session_key=-s-abc.def" +
+	"&session_secret_key=0f1e2d3c4b5a69788796a5b4c3d2e1f0" +
+	"&web_server=ok.ru"
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	secrets := map[string]string{
+		"CBAFGHJKLMNOPQRST": "SECRETKEY123",
+	}
+
+	tests := []struct {
+		name          string
+		rawQuery      string
+		clientSecrets map[string]string
+		wantValid     bool
+	}{
+		{
+			name:          "Missing secrets",
+			rawQuery:      testQuery + "&sig=eb0495412c7f1b55feb8161cd9c34499",
+			clientSecrets: nil,
+			wantValid:     false,
+		},
+		{
+			name:          "No signature param",
+			rawQuery:      testQuery,
+			clientSecrets: secrets,
+			wantValid:     false,
+		},
+		{
+			name:          "Unknown application key",
+			rawQuery:      testQuery + "&sig=eb0495412c7f1b55feb8161cd9c34499",
+			clientSecrets: map[string]string{"OTHER": "SECRETKEY123"},
+			wantValid:     false,
+		},
+		{
+			name:          "Invalid signature",
+			rawQuery:      testQuery + "&sig=00000000000000000000000000000000",
+			clientSecrets: secrets,
+			wantValid:     false,
+		},
+		{
+			name:          "Malformed signature",
+			rawQuery:      testQuery + "&sig=zz0495412c7f1b55feb8161cd9c34499",
+			clientSecrets: secrets,
+			wantValid:     false,
+		},
+		{
+			name:          "Malformed query",
+			rawQuery:      testQuery + "&sig=eb0495412c7f1b55feb8161cd9c34499&x=%gh",
+			clientSecrets: secrets,
+			wantValid:     false,
+		},
+		{
+			name:          "Extra parameter",
+			rawQuery:      testQuery + "&sig=eb0495412c7f1b55feb8161cd9c34499&x=1",
+			clientSecrets: secrets,
+			wantValid:     false,
+		},
+		{
+			name:          "Signed but wrong auth_sig",
+			rawQuery:      strings.Replace(testQuery, "e7562d245a873267132f8a1bb33c122b", "00000000000000000000000000000000", 1) + "&sig=17eb48de5b07590c58a8625b84972d45",
+			clientSecrets: secrets,
+			wantValid:     false,
+		},
+		{
+			name:          "Valid signature",
+			rawQuery:      testQuery + "&sig=eb0495412c7f1b55feb8161cd9c34499",
+			clientSecrets: secrets,
+			wantValid:     true,
+		},
+		{
+			name:          "Valid with question mark and uppercase hex",
+			rawQuery:      "?" + testQuery + "&sig=EB0495412C7F1B55FEB8161CD9C34499",
+			clientSecrets: secrets,
+			wantValid:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := Verify(tt.rawQuery, tt.clientSecrets)
+			if ok != tt.wantValid {
+				t.Errorf("Verify() = %v, want %v", ok, tt.wantValid)
+			}
+			if ok && p == nil {
+				t.Error("Expected non-nil *Params on valid signature")
+			}
+		})
+	}
+}
+
+func TestVerify_Params(t *testing.T) {
+	p, ok := Verify(testQuery+"&sig=eb0495412c7f1b55feb8161cd9c34499", map[string]string{
+		"CBAFGHJKLMNOPQRST": "SECRETKEY123",
+	})
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	if p.LoggedUserID != 575426848451 {
+		t.Errorf("LoggedUserID = %d", p.LoggedUserID)
+	}
+	if p.ApiServer != "https://api.ok.ru/" || p.CustomArgs != "ref=ad 1" {
+		t.Errorf("unescaped values = %q, %q", p.ApiServer, p.CustomArgs)
+	}
+	if !p.Authorized || p.FirstStart {
+		t.Errorf("flags = %v, %v", p.Authorized, p.FirstStart)
+	}
+	if p.Sig != "eb0495412c7f1b55feb8161cd9c34499" {
+		t.Errorf("Sig = %q", p.Sig)
+	}
+}
+
+func TestVerifySession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rawQuery  string
+		secret    string
+		wantValid bool
+	}{
+		{"Missing secret", testQuery + "&sig=5572cc62992b65cb4d973d6faa047d43", "", false},
+		{"Application secret rejected", testQuery + "&sig=eb0495412c7f1b55feb8161cd9c34499", "0f1e2d3c4b5a69788796a5b4c3d2e1f0", false},
+		{"Valid session signature", testQuery + "&sig=5572cc62992b65cb4d973d6faa047d43", "0f1e2d3c4b5a69788796a5b4c3d2e1f0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := VerifySession(tt.rawQuery, tt.secret)
+			if ok != tt.wantValid {
+				t.Errorf("VerifySession() = %v, want %v", ok, tt.wantValid)
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	secrets := map[string]string{
+		"CBAFGHJKLMNOPQRST": "SECRETKEY123",
+	}
+	rawQuery := testQuery + "&sig=eb0495412c7f1b55feb8161cd9c34499"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Verify(rawQuery, secrets)
+	}
+}
@@ -0,0 +1,172 @@
+package okpay
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// TxState is the processing state of a transaction in a TransactionStore.
+type TxState int
+
+const (
+	TxNew        TxState = iota // Never seen: the caller now owns processing
+	TxInProgress                // Being processed by another request
+	TxDone                      // Already processed successfully
+)
+
+// TransactionStore guards against processing the same transaction twice.
+// OK repeats a notification until it receives a success reply, so the same
+// transaction_id may arrive several times, possibly concurrently.
+type TransactionStore interface {
+	// Begin atomically marks id as in progress if it is new and reports
+	// the state id had before the call.
+	Begin(id string) TxState
+
+	// Commit marks id as successfully processed.
+	Commit(id string)
+
+	// Rollback forgets id so that a retried notification is processed again.
+	Rollback(id string)
+}
+
+// Handler is an http.Handler for the callbacks.payment endpoint.
+//
+// It verifies the request signature, skips already processed transactions,
+// calls OnPayment and writes the XML reply OK expects.
+type Handler struct {
+	// Secrets maps application keys to their secret keys.
+	Secrets map[string]string
+
+	// OnPayment processes a verified payment. Returning nil confirms it;
+	// returning an *Error rejects it with the given code.
+	OnPayment func(ctx context.Context, p *Payment) *Error
+
+	// Transactions deduplicates notifications by transaction_id.
+	// When nil, every notification is passed to OnPayment.
+	Transactions TransactionStore
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p, ok := Verify(r.URL.RawQuery, h.Secrets)
+	if !ok {
+		WriteError(w, &Error{Code: ErrParamSignature})
+		return
+	}
+	if p.Method != "" && p.Method != MethodPayment {
+		WriteError(w, &Error{Code: ErrMethod})
+		return
+	}
+	if p.TransactionID == "" || p.ProductCode == "" {
+		WriteError(w, &Error{Code: ErrCallbackInvalidPayment})
+		return
+	}
+
+	committed := false
+	if h.Transactions != nil {
+		switch h.Transactions.Begin(p.TransactionID) {
+		case TxDone:
+			// Already confirmed once: confirm again without side effects
+			WriteSuccess(w)
+			return
+		case TxInProgress:
+			// Let OK retry once the concurrent request has finished
+			WriteError(w, &Error{Code: ErrService})
+			return
+		}
+
+		// Forget the transaction unless it was committed, also if OnPayment
+		// panics, so that it does not stay in progress and block retries
+		defer func() {
+			if !committed {
+				h.Transactions.Rollback(p.TransactionID)
+			}
+		}()
+	}
+
+	var e *Error
+	if h.OnPayment != nil {
+		e = h.OnPayment(r.Context(), p)
+	}
+	if e != nil {
+		WriteError(w, e)
+		return
+	}
+
+	if h.Transactions != nil {
+		h.Transactions.Commit(p.TransactionID)
+		committed = true
+	}
+	WriteSuccess(w)
+}
+
+// MemoryStore is an in-memory TransactionStore bounded by capacity.
+// When full, the oldest committed transactions are forgotten first.
+// It is suitable for a single process; use a shared store otherwise.
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	states   map[string]TxState
+	done     []string // Committed IDs in commit order, used as a ring
+	next     int      // Position of the oldest entry in done once it is full
+}
+
+// NewMemoryStore creates a MemoryStore remembering up to capacity
+// committed transactions.
+func NewMemoryStore(capacity int) *MemoryStore {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &MemoryStore{
+		capacity: capacity,
+		states:   make(map[string]TxState, capacity),
+		done:     make([]string, 0, capacity),
+	}
+}
+
+// Begin implements TransactionStore.
+func (s *MemoryStore) Begin(id string) TxState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.states[id]; ok {
+		return state
+	}
+	// The ID may point into request buffers, keep a private copy
+	s.states[strings.Clone(id)] = TxInProgress
+	return TxNew
+}
+
+// Commit implements TransactionStore.
+func (s *MemoryStore) Commit(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.states[id] == TxDone {
+		return
+	}
+	// Assigning to an existing key replaces the stored key, so store a
+	// private copy as Begin does
+	id = strings.Clone(id)
+	s.states[id] = TxDone
+
+	if len(s.done) < s.capacity {
+		s.done = append(s.done, id)
+		return
+	}
+	delete(s.states, s.done[s.next])
+	s.done[s.next] = id
+	s.next = (s.next + 1) % s.capacity
+}
+
+// Rollback implements TransactionStore.
+func (s *MemoryStore) Rollback(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.states[id] == TxInProgress {
+		delete(s.states, id)
+	}
+}
@@ -0,0 +1,172 @@
+package okpay
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func serve(h http.Handler, rawQuery string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?"+rawQuery, nil))
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	calls := 0
+	fail := false
+	h := &Handler{
+		Secrets: testSecrets,
+		OnPayment: func(ctx context.Context, p *Payment) *Error {
+			calls++
+			if fail {
+				return &Error{Code: ErrCallbackInvalidPayment}
+			}
+			return nil
+		},
+		Transactions: NewMemoryStore(16),
+	}
+
+	rec := serve(h, testQuery+"&sig=00000000000000000000000000000000")
+	if got := rec.Header().Get("invocation-error"); got != "104" {
+		t.Errorf("bad signature: invocation-error = %q, want 104", got)
+	}
+
+	fail = true
+	rec = serve(h, testQuery+"&sig="+testSig)
+	wantError := `<?xml version="1.0" encoding="UTF-8"?>
+<ns2:error_response xmlns:ns2='http://api.forticom.com/1.0/'>
+    <error_code>1001</error_code>
+    <error_msg>CALLBACK_INVALID_PAYMENT : Payment is invalid and can not be processed</error_msg>
+</ns2:error_response>`
+	if rec.Body.String() != wantError {
+		t.Errorf("rejected payment: body = %q", rec.Body.String())
+	}
+	if got := rec.Header().Get("invocation-error"); got != "1001" {
+		t.Errorf("rejected payment: invocation-error = %q, want 1001", got)
+	}
+
+	fail = false
+	rec = serve(h, testQuery+"&sig="+testSig)
+	wantSuccess := `<?xml version="1.0" encoding="UTF-8"?>
+<callbacks_payment_response xmlns="http://api.forticom.com/1.0/">
+true
+</callbacks_payment_response>`
+	if rec.Body.String() != wantSuccess {
+		t.Errorf("accepted payment: body = %q", rec.Body.String())
+	}
+	if rec.Header().Get("invocation-error") != "" {
+		t.Error("accepted payment: unexpected invocation-error header")
+	}
+
+	// A repeated notification is confirmed without calling OnPayment again
+	rec = serve(h, testQuery+"&sig="+testSig)
+	if rec.Body.String() != wantSuccess {
+		t.Errorf("duplicate payment: body = %q", rec.Body.String())
+	}
+	if calls != 2 {
+		t.Errorf("OnPayment called %d times, want 2", calls)
+	}
+}
+
+func TestHandler_PanicRollsBack(t *testing.T) {
+	panics := true
+	calls := 0
+	h := &Handler{
+		Secrets: testSecrets,
+		OnPayment: func(ctx context.Context, p *Payment) *Error {
+			calls++
+			if panics {
+				panic("payment backend down")
+			}
+			return nil
+		},
+		Transactions: NewMemoryStore(16),
+	}
+
+	// net/http recovers handler panics; the transaction must not stay in progress
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("ServeHTTP did not propagate the panic")
+			}
+		}()
+		serve(h, testQuery+"&sig="+testSig)
+	}()
+
+	panics = false
+	rec := serve(h, testQuery+"&sig="+testSig)
+	if got := rec.Header().Get("invocation-error"); got != "" || calls != 2 {
+		t.Errorf("retry after panic: invocation-error = %q, OnPayment calls = %d; want success after 2 calls", got, calls)
+	}
+}
+
+func TestWriteError_Escapes(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteError(rec, &Error{Code: ErrSystem, Message: "a < b & c"})
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<ns2:error_response xmlns:ns2='http://api.forticom.com/1.0/'>
+    <error_code>9999</error_code>
+    <error_msg>a &lt; b &amp; c</error_msg>
+</ns2:error_response>`
+	if rec.Body.String() != want {
+		t.Errorf("WriteError() body = %q", rec.Body.String())
+	}
+}
+
+func TestMemoryStore_CopiesIDs(t *testing.T) {
+	s := NewMemoryStore(2)
+
+	buf := []byte("tx-1")
+	id := unsafe.String(&buf[0], len(buf)) // Like an ID read from a request buffer
+	s.Begin(id)
+	s.Commit(id)
+	copy(buf, "tx-2")
+
+	if got := s.Begin("tx-1"); got != TxDone {
+		t.Errorf("Begin(tx-1) after the request buffer was reused = %v, want TxDone", got)
+	}
+}
+
+func TestWriteError_InvalidPayment(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteError(rec, &Error{Code: ErrInvalidPayment})
+	if body := rec.Body.String(); !strings.Contains(body, "<error_code>1003</error_code>") ||
+		!strings.Contains(body, "<error_msg>INVALID_PAYMENT : Invalid payment</error_msg>") {
+		t.Errorf("WriteError() body = %q", body)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore(2)
+
+	if s.Begin("a") != TxNew {
+		t.Fatal("first Begin must be TxNew")
+	}
+	if s.Begin("a") != TxInProgress {
+		t.Fatal("second Begin must be TxInProgress")
+	}
+	s.Rollback("a")
+	if s.Begin("a") != TxNew {
+		t.Fatal("Begin after Rollback must be TxNew")
+	}
+	s.Commit("a")
+	if s.Begin("a") != TxDone {
+		t.Fatal("Begin after Commit must be TxDone")
+	}
+
+	// Committing beyond capacity forgets the oldest transaction
+	for _, id := range []string{"b", "c"} {
+		s.Begin(id)
+		s.Commit(id)
+	}
+	if s.Begin("a") != TxNew {
+		t.Error("oldest transaction must be evicted")
+	}
+	if s.Begin("c") != TxDone {
+		t.Error("newest transaction must be kept")
+	}
+}
@@ -0,0 +1,92 @@
+// Package okpay provides types and functionality for handling Odnoklassniki (OK)
+// in-app payment notifications delivered to the callbacks.payment endpoint.
+package okpay
+
+import (
+	"strconv"
+)
+
+// MethodPayment is the method name OK sends with payment notifications.
+const MethodPayment = "callbacks.payment"
+
+// Payment represents the parameters of a callbacks.payment notification.
+// OK sends them as a signed GET request when a user buys a product.
+//
+// OK Payments documentation: https://apiok.ru/en/dev/methods/rest/callbacks/callbacks.payment
+type Payment struct {
+	// Method is the notification method, always "callbacks.payment"
+	Method string
+
+	// ApplicationKey is the public key of the application
+	ApplicationKey string
+
+	// CallID is a unique identifier of the notification call
+	CallID string
+
+	// UID is the ID of the OK user who made the purchase
+	UID int64
+
+	// TransactionID is a unique identifier of the transaction in OK.
+	// Repeated notifications for the same purchase carry the same ID.
+	TransactionID string
+
+	// TransactionTime is the time of the transaction as sent by OK
+	// (e.g. "2024-03-11 18:09:05")
+	TransactionTime string
+
+	// ProductCode is the merchant's product identifier
+	ProductCode string
+
+	// ProductOption is an optional product variant
+	ProductOption string
+
+	// Amount is the price paid, in OKs
+	Amount int
+
+	// ExtraAttributes is an optional JSON string with additional data
+	ExtraAttributes string
+
+	// Sig is the MD5 signature of the request
+	Sig string
+}
+
+// set assigns a value to the appropriate struct field based on the parameter name.
+//
+// Parameters:
+//   - key: The parameter name from OK (e.g. "uid", "amount")
+//   - value: The raw string value from the request
+//
+// Note:
+// - Numeric values are automatically converted from string
+// - Unknown parameters are silently ignored
+// - Conversion errors result in zero values
+func (p *Payment) set(key, value string) {
+	switch key {
+	case "method":
+		p.Method = value
+	case "application_key":
+		p.ApplicationKey = value
+	case "call_id":
+		p.CallID = value
+	case "uid":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			p.UID = v
+		}
+	case "transaction_id":
+		p.TransactionID = value
+	case "transaction_time":
+		p.TransactionTime = value
+	case "product_code":
+		p.ProductCode = value
+	case "product_option":
+		p.ProductOption = value
+	case "amount":
+		if v, err := strconv.Atoi(value); err == nil {
+			p.Amount = v
+		}
+	case "extra_attributes":
+		p.ExtraAttributes = value
+	case "sig":
+		p.Sig = value
+	}
+}
@@ -0,0 +1,43 @@
+package okpay
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// signQuery appends the OK signature of rawQuery made with secret.
+func signQuery(rawQuery, secret string) string {
+	q, _ := url.ParseQuery(rawQuery)
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + "=" + q.Get(k))
+	}
+	b.WriteString(secret)
+	sum := md5.Sum([]byte(b.String()))
+	return rawQuery + "&sig=" + hex.EncodeToString(sum[:])
+}
+
+func TestVerify_PooledBuffers(t *testing.T) {
+	first, ok := Verify(signQuery("application_key=CBAFGHJKLMNOPQRST&transaction_id=tx+one", "OKSECRET"), testSecrets)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	// A second verification reuses the pooled unescape buffer
+	if _, ok := Verify(signQuery("application_key=CBAFGHJKLMNOPQRST&transaction_id=ZZZ+ZZZ", "OKSECRET"), testSecrets); !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	if first.TransactionID != "tx one" {
+		t.Errorf("TransactionID after reuse = %q, want %q", first.TransactionID, "tx one")
+	}
+}
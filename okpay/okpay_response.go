@@ -0,0 +1,95 @@
+package okpay
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// ErrorCode is an OK API error code returned in error responses.
+// OK treats every error as a failed notification and retries it later,
+// except for CALLBACK_INVALID_PAYMENT, which is final.
+type ErrorCode int
+
+// Error codes OK accepts in callbacks.payment error responses.
+const (
+	ErrUnknown                ErrorCode = 1    // UNKNOWN: unknown error
+	ErrService                ErrorCode = 2    // SERVICE: service temporarily unavailable
+	ErrMethod                 ErrorCode = 3    // METHOD: method does not exist
+	ErrParamSignature         ErrorCode = 104  // PARAM_SIGNATURE: invalid signature
+	ErrCallbackInvalidPayment ErrorCode = 1001 // CALLBACK_INVALID_PAYMENT: payment is invalid
+	ErrInvalidPayment         ErrorCode = 1003 // INVALID_PAYMENT: payment could not be processed
+	ErrSystem                 ErrorCode = 9999 // SYSTEM: critical system error
+)
+
+// errorNames maps known codes to the message prefix OK uses for them.
+var errorNames = map[ErrorCode]string{
+	ErrUnknown:                "UNKNOWN : Unknown error",
+	ErrService:                "SERVICE : Service temporary unavailable",
+	ErrMethod:                 "METHOD : Method does not exist",
+	ErrParamSignature:         "PARAM_SIGNATURE : Invalid signature",
+	ErrCallbackInvalidPayment: "CALLBACK_INVALID_PAYMENT : Payment is invalid and can not be processed",
+	ErrInvalidPayment:         "INVALID_PAYMENT : Invalid payment",
+	ErrSystem:                 "SYSTEM : Critical system error",
+}
+
+// Error is an error reply to a payment notification.
+// Message is optional; when empty the standard message for Code is used.
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return "okpay: " + strconv.Itoa(int(e.Code)) + ": " + e.message()
+}
+
+// message returns the text placed into error_msg.
+func (e *Error) message() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if name, ok := errorNames[e.Code]; ok {
+		return name
+	}
+	return errorNames[ErrUnknown]
+}
+
+const (
+	// successResponse is the exact body OK expects for a processed payment.
+	successResponse = `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<callbacks_payment_response xmlns="http://api.forticom.com/1.0/">` + "\n" +
+		"true\n" +
+		"</callbacks_payment_response>"
+
+	errorResponseHead = `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<ns2:error_response xmlns:ns2='http://api.forticom.com/1.0/'>` + "\n" +
+		"    <error_code>"
+	errorResponseMsg  = "</error_code>\n    <error_msg>"
+	errorResponseTail = "</error_msg>\n</ns2:error_response>"
+)
+
+// WriteSuccess writes the response confirming that the payment was processed.
+func WriteSuccess(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, successResponse)
+}
+
+// WriteError writes an error response for e. Besides the XML body, OK
+// requires the error code in the "invocation-error" header.
+func WriteError(w http.ResponseWriter, e *Error) {
+	code := strconv.Itoa(int(e.Code))
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("invocation-error", code)
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = io.WriteString(w, errorResponseHead)
+	_, _ = io.WriteString(w, code)
+	_, _ = io.WriteString(w, errorResponseMsg)
+	_ = xml.EscapeText(w, []byte(e.message()))
+	_, _ = io.WriteString(w, errorResponseTail)
+}
@@ -0,0 +1,96 @@
+// Package okpay provides functionality for verifying Odnoklassniki (OK)
+// payment notifications and replying to them in the XML format OK expects.
+package okpay
+
+import (
+	"crypto/md5"
+
+	"github.com/elum-utils/sign/canon"
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// Verify validates the signature of a callbacks.payment notification.
+//
+// Parameters:
+//   - rawQuery: The raw URL query string of the notification request
+//   - secrets: A map of application keys (application_key) to their secret keys
+//
+// Returns:
+//   - *Payment: Parsed payment parameters if verification succeeds
+//   - bool: true if signature is valid, false otherwise
+//
+// The signature is the MD5 hash of all key=value pairs except sig,
+// sorted by key and concatenated without separators, followed by the secret.
+func Verify(rawQuery string, secrets map[string]string) (*Payment, bool) {
+	// Early return if no secrets provided
+	if len(secrets) == 0 {
+		return nil, false
+	}
+
+	var appKey, sig string
+
+	// Get key-value pairs from sync.Pool to reduce allocations
+	pairsPtr := utils.KVPool.Get().(*utils.KVSlice)
+	pairs := (*pairsPtr)[:0] // Slice reset without reallocation
+	defer func() { utils.PutKV(pairsPtr, pairs) }()
+
+	// Get temporary buffer for URL unescaping from pool
+	tmpBufPtr := utils.TmpBufPool.Get().(*[]byte)
+	defer utils.TmpBufPool.Put(tmpBufPtr)
+
+	// Parse query string parameters, skipping those without values
+	it := canon.NewIterator(rawQuery, (*tmpBufPtr)[:0], canon.Options{})
+	for p, ok := it.Next(); ok; p, ok = it.Next() {
+		// Categorize parameters
+		switch p.Key {
+		case "sig":
+			sig = p.Value // Store signature separately
+		case "application_key":
+			appKey = p.Value // Store application key for secret lookup
+			pairs = append(pairs, p)
+		default:
+			pairs = append(pairs, p)
+		}
+	}
+	if it.Err() != nil {
+		return nil, false // Oversized input or invalid escapes
+	}
+
+	// Verify required parameters exist
+	if appKey == "" || sig == "" {
+		return nil, false
+	}
+
+	// Lookup secret for this application
+	secret, ok := secrets[appKey]
+	if !ok {
+		return nil, false // Unknown application key
+	}
+
+	// Sort parameters lexicographically by key
+	pairs.Sort()
+
+	// Get buffer for signature string from pool
+	bufPtr := utils.BufCanonicalPool.Get().(*[]byte)
+	buf := (*bufPtr)[:0]
+	defer func() { utils.PutBuf(&utils.BufCanonicalPool, bufPtr, buf) }()
+
+	// Detach stored values from the pooled unescape buffer
+	d := utils.NewDetacher(*tmpBufPtr, it.Buffer())
+
+	payment := &Payment{} // Only allocation for result
+	for _, p := range pairs {
+		payment.set(p.Key, d.String(p.Value))
+	}
+	payment.Sig = d.String(sig)
+
+	// Signature string format: key=value pairs without separators,
+	// followed by the secret
+	buf = canon.AppendConcatSecret(buf, pairs, secret)
+
+	if !utils.EqualMD5Hex(md5.Sum(buf), sig) {
+		return nil, false
+	}
+
+	return payment, true
+}
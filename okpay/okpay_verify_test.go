@@ -0,0 +1,80 @@
+package okpay
+
+import (
+	"testing"
+)
+
+const testQuery = "method=callbacks.payment" +
+	"&application_key=CBAFGHJKLMNOPQRST" +
+	"&call_id=1700000000123" +
+	"&uid=575426848451" +
+	"&transaction_id=1234567890" +
+	"&transaction_time=2024-03-11+18%3A09%3A05" +
+	"&product_code=gems_100" +
+	"&amount=100"
+
+const testSig = "12437fc1e4d7aa42ab37af8865adc8fc"
+
+var testSecrets = map[string]string{
+	"CBAFGHJKLMNOPQRST": "OKSECRET",
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		rawQuery      string
+		clientSecrets map[string]string
+		wantValid     bool
+	}{
+		{"Missing secrets", testQuery + "&sig=" + testSig, nil, false},
+		{"No signature param", testQuery, testSecrets, false},
+		{"Empty signature", testQuery + "&sig=", testSecrets, false},
+		{"Invalid signature", testQuery + "&sig=INVALIDSIG", testSecrets, false},
+		{"Tampered amount", testQuery + "0&sig=" + testSig, testSecrets, false},
+		{"Valid signature", testQuery + "&sig=" + testSig, testSecrets, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := Verify(tt.rawQuery, tt.clientSecrets)
+			if ok != tt.wantValid {
+				t.Errorf("Verify() = %v, want %v", ok, tt.wantValid)
+			}
+			if ok && p == nil {
+				t.Error("Expected non-nil *Payment on valid signature")
+			}
+		})
+	}
+}
+
+func TestVerify_Payment(t *testing.T) {
+	p, ok := Verify(testQuery+"&sig="+testSig, testSecrets)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	want := Payment{
+		Method:          MethodPayment,
+		ApplicationKey:  "CBAFGHJKLMNOPQRST",
+		CallID:          "1700000000123",
+		UID:             575426848451,
+		TransactionID:   "1234567890",
+		TransactionTime: "2024-03-11 18:09:05",
+		ProductCode:     "gems_100",
+		Amount:          100,
+		Sig:             testSig,
+	}
+	if *p != want {
+		t.Errorf("Verify() = %+v, want %+v", *p, want)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	rawQuery := testQuery + "&sig=" + testSig
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Verify(rawQuery, testSecrets)
+	}
+}
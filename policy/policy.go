@@ -0,0 +1,128 @@
+// Package policy provides declarative authorization rules evaluated over
+// verified launch data from the tma and vkma packages.
+//
+// Rules are plain predicates that return a structured Denial when access
+// must be refused. They compose with All, Any and Not, so checks such as
+// "admins of group 42 on mobile platforms, unless launched from an ad"
+// can be written once and reused across handlers.
+package policy
+
+import (
+	"strings"
+
+	"github.com/elum-utils/sign/tma"
+	"github.com/elum-utils/sign/vkma"
+)
+
+// Input is the verified launch context a Rule is evaluated against.
+//
+// Only the fields relevant to the platform in use need to be set: rules
+// targeting VK deny when VK is nil, and rules targeting Telegram deny when
+// TMA (or User for user-level checks) is nil.
+type Input struct {
+	// VK holds parameters returned by vkma.Verify.
+	VK *vkma.Params
+
+	// TMA holds parameters returned by tma.Verify.
+	TMA *tma.Params
+
+	// User holds the decoded Telegram user, usually from TMA.User().
+	User *tma.User
+}
+
+// Denial describes why a Rule refused access.
+//
+// Rule is a short machine-readable identifier of the failed check
+// (e.g. "vk_group_role"), Reason is a human-readable explanation and
+// Causes lists the individual denials collected by Any.
+type Denial struct {
+	Rule   string    `json:"rule"`
+	Reason string    `json:"reason"`
+	Causes []*Denial `json:"causes,omitempty"`
+}
+
+// Error implements the error interface so a Denial can be returned
+// wherever an error is expected.
+func (d *Denial) Error() string {
+	if d.Reason == "" {
+		return "policy: denied by " + d.Rule
+	}
+	return "policy: " + d.Rule + ": " + d.Reason
+}
+
+// Rule is a single authorization predicate.
+// It returns nil when access is allowed and a Denial otherwise.
+type Rule func(in *Input) *Denial
+
+// Check evaluates rule against in.
+// A nil rule allows everything; a nil input is treated as empty.
+func Check(rule Rule, in *Input) *Denial {
+	if rule == nil {
+		return nil
+	}
+	if in == nil {
+		in = &Input{}
+	}
+	return rule(in)
+}
+
+// All returns a Rule that allows access only if every rule allows it.
+// Rules are evaluated in order and the first denial is returned.
+func All(rules ...Rule) Rule {
+	return func(in *Input) *Denial {
+		for _, r := range rules {
+			if d := Check(r, in); d != nil {
+				return d
+			}
+		}
+		return nil
+	}
+}
+
+// Any returns a Rule that allows access if at least one rule allows it.
+// When every rule denies, the returned Denial carries all of them in Causes.
+// Any with no rules denies.
+func Any(rules ...Rule) Rule {
+	return func(in *Input) *Denial {
+		causes := make([]*Denial, 0, len(rules))
+		for _, r := range rules {
+			d := Check(r, in)
+			if d == nil {
+				return nil
+			}
+			causes = append(causes, d)
+		}
+
+		reasons := make([]string, len(causes))
+		for i, c := range causes {
+			reasons[i] = c.Reason
+		}
+		return &Denial{
+			Rule:   "any",
+			Reason: "no alternative matched: " + strings.Join(reasons, "; "),
+			Causes: causes,
+		}
+	}
+}
+
+// Not returns a Rule that inverts rule: it denies with the given name and
+// reason when rule allows, and allows when rule denies.
+func Not(rule Rule, name, reason string) Rule {
+	return func(in *Input) *Denial {
+		if Check(rule, in) != nil {
+			return nil
+		}
+		return &Denial{Rule: name, Reason: reason}
+	}
+}
+
+// Predicate builds a Rule from a boolean function.
+// The rule denies with the given name and reason when fn returns false.
+func Predicate(name, reason string, fn func(in *Input) bool) Rule {
+	return func(in *Input) *Denial {
+		if fn(in) {
+			return nil
+		}
+		return &Denial{Rule: name, Reason: reason}
+	}
+}
@@ -0,0 +1,42 @@
+package policy
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Middleware returns HTTP middleware that enforces rule on every request.
+//
+// Parameters:
+//   - rule: The rule to evaluate
+//   - input: Extracts the verified launch data from the request
+//     (typically from the request context filled by a verification step)
+//   - denied: Writes the response for a refused request; when nil,
+//     a 403 response with the Denial encoded as JSON is written
+//
+// Requests for which input returns nil are evaluated against an empty Input.
+// Middleware panics if input is nil, instead of failing on every request.
+func Middleware(rule Rule, input func(r *http.Request) *Input, denied func(w http.ResponseWriter, r *http.Request, d *Denial)) func(http.Handler) http.Handler {
+	if input == nil {
+		panic("policy: Middleware called with nil input")
+	}
+	if denied == nil {
+		denied = writeDenial
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if d := Check(rule, input(r)); d != nil {
+				denied(w, r, d)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeDenial is the default denial writer used by Middleware.
+func writeDenial(w http.ResponseWriter, _ *http.Request, d *Denial) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(d)
+}
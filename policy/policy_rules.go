@@ -0,0 +1,110 @@
+package policy
+
+import (
+	"strconv"
+
+	"github.com/elum-utils/sign/vkma"
+)
+
+// VKGroupRole allows users launched from the community groupID whose
+// vk_viewer_group_role is one of roles.
+//
+// Example:
+//
+//	moderators := policy.VKGroupRole(42, vkma.RoleModer, vkma.RoleAdmin)
+func VKGroupRole(groupID int, roles ...vkma.Role) Rule {
+	return func(in *Input) *Denial {
+		if in.VK == nil {
+			return &Denial{Rule: "vk_group_role", Reason: "not a VK launch"}
+		}
+		if in.VK.VkGroupID != groupID {
+			return &Denial{
+				Rule:   "vk_group_role",
+				Reason: "launched outside group " + strconv.Itoa(groupID),
+			}
+		}
+		for _, role := range roles {
+			if in.VK.VkViewerGroupRole == role {
+				return nil
+			}
+		}
+		return &Denial{
+			Rule:   "vk_group_role",
+			Reason: "role " + strconv.Quote(string(in.VK.VkViewerGroupRole)) + " is not allowed",
+		}
+	}
+}
+
+// VKGroupAdmin allows only administrators of the community groupID.
+func VKGroupAdmin(groupID int) Rule {
+	return VKGroupRole(groupID, vkma.RoleAdmin)
+}
+
+// VKPlatform allows launches from the listed vk_platform values only.
+func VKPlatform(platforms ...vkma.Platform) Rule {
+	return func(in *Input) *Denial {
+		if in.VK == nil {
+			return &Denial{Rule: "vk_platform", Reason: "not a VK launch"}
+		}
+		for _, p := range platforms {
+			if in.VK.VkPlatform == p {
+				return nil
+			}
+		}
+		return &Denial{
+			Rule:   "vk_platform",
+			Reason: "platform " + strconv.Quote(string(in.VK.VkPlatform)) + " is not allowed",
+		}
+	}
+}
+
+// DenyVKRef refuses launches whose vk_ref is one of refs.
+// Launches from any other source, and non-VK launches, are allowed.
+func DenyVKRef(refs ...vkma.Referral) Rule {
+	return func(in *Input) *Denial {
+		if in.VK == nil {
+			return nil
+		}
+		for _, ref := range refs {
+			if in.VK.VkRef == ref {
+				return &Denial{
+					Rule:   "vk_ref",
+					Reason: "launch source " + strconv.Quote(string(ref)) + " is denied",
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// TMAChatType allows Telegram launches whose chat_type is one of types
+// (e.g. "private", "group", "supergroup", "channel", "sender").
+func TMAChatType(types ...string) Rule {
+	return func(in *Input) *Denial {
+		if in.TMA == nil {
+			return &Denial{Rule: "tma_chat_type", Reason: "not a Telegram launch"}
+		}
+		for _, t := range types {
+			if in.TMA.ChatType == t {
+				return nil
+			}
+		}
+		return &Denial{
+			Rule:   "tma_chat_type",
+			Reason: "chat type " + strconv.Quote(in.TMA.ChatType) + " is not allowed",
+		}
+	}
+}
+
+// TMAPremium allows only Telegram users with an active Premium subscription.
+func TMAPremium() Rule {
+	return func(in *Input) *Denial {
+		if in.User == nil {
+			return &Denial{Rule: "tma_premium", Reason: "no Telegram user"}
+		}
+		if !in.User.IsPremium {
+			return &Denial{Rule: "tma_premium", Reason: "Telegram Premium is required"}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+package policy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elum-utils/sign/tma"
+	"github.com/elum-utils/sign/vkma"
+)
+
+func TestRules(t *testing.T) {
+	t.Parallel()
+
+	admin := &Input{VK: &vkma.Params{
+		VkGroupID:         42,
+		VkViewerGroupRole: vkma.RoleAdmin,
+		VkPlatform:        vkma.MobileAndroid,
+		VkRef:             vkma.Catalog,
+	}}
+	member := &Input{VK: &vkma.Params{
+		VkGroupID:         42,
+		VkViewerGroupRole: vkma.RoleMember,
+		VkPlatform:        vkma.DesktopWeb,
+		VkRef:             vkma.Widget,
+	}}
+	premium := &Input{
+		TMA:  &tma.Params{ChatType: "private"},
+		User: &tma.User{ID: 1, IsPremium: true},
+	}
+	regular := &Input{
+		TMA:  &tma.Params{ChatType: "group"},
+		User: &tma.User{ID: 2},
+	}
+
+	tests := []struct {
+		name      string
+		rule      Rule
+		input     *Input
+		wantAllow bool
+		wantRule  string
+	}{
+		{"nil rule allows", nil, nil, true, ""},
+		{"group admin allowed", VKGroupAdmin(42), admin, true, ""},
+		{"group member denied", VKGroupAdmin(42), member, false, "vk_group_role"},
+		{"other group denied", VKGroupAdmin(7), admin, false, "vk_group_role"},
+		{"group role on telegram denied", VKGroupAdmin(42), premium, false, "vk_group_role"},
+		{"group role list", VKGroupRole(42, vkma.RoleMember, vkma.RoleAdmin), member, true, ""},
+		{"platform allowed", VKPlatform(vkma.MobileAndroid, vkma.MobileIPhone), admin, true, ""},
+		{"platform denied", VKPlatform(vkma.MobileAndroid), member, false, "vk_platform"},
+		{"ref denied", DenyVKRef(vkma.Widget), member, false, "vk_ref"},
+		{"ref allowed", DenyVKRef(vkma.Widget), admin, true, ""},
+		{"ref ignores telegram", DenyVKRef(vkma.Widget), premium, true, ""},
+		{"chat type allowed", TMAChatType("private"), premium, true, ""},
+		{"chat type denied", TMAChatType("private"), regular, false, "tma_chat_type"},
+		{"premium allowed", TMAPremium(), premium, true, ""},
+		{"premium denied", TMAPremium(), regular, false, "tma_premium"},
+		{"all allowed", All(VKGroupAdmin(42), VKPlatform(vkma.MobileAndroid)), admin, true, ""},
+		{"all first denial", All(VKGroupAdmin(42), VKPlatform(vkma.MobileIPhone)), admin, false, "vk_platform"},
+		{"any allowed", Any(VKGroupAdmin(42), TMAPremium()), premium, true, ""},
+		{"any denied", Any(VKGroupAdmin(42), TMAPremium()), regular, false, "any"},
+		{"empty any denied", Any(), admin, false, "any"},
+		{"not inverts", Not(TMAPremium(), "not_premium", "premium users only elsewhere"), premium, false, "not_premium"},
+		{"not allows", Not(TMAPremium(), "not_premium", ""), regular, true, ""},
+		{"predicate", Predicate("odd", "even ids", func(in *Input) bool { return in.User.ID%2 == 1 }), premium, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Check(tt.rule, tt.input)
+			if (d == nil) != tt.wantAllow {
+				t.Fatalf("Check() = %v, want allow %v", d, tt.wantAllow)
+			}
+			if d != nil && d.Rule != tt.wantRule {
+				t.Errorf("Denial.Rule = %q, want %q", d.Rule, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestAnyCauses(t *testing.T) {
+	d := Check(Any(VKGroupAdmin(42), TMAPremium()), &Input{})
+	if d == nil || len(d.Causes) != 2 {
+		t.Fatalf("expected two causes, got %+v", d)
+	}
+	if d.Causes[0].Rule != "vk_group_role" || d.Causes[1].Rule != "tma_premium" {
+		t.Errorf("unexpected causes: %+v, %+v", d.Causes[0], d.Causes[1])
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	input := func(r *http.Request) *Input {
+		if r.URL.Query().Get("premium") == "1" {
+			return &Input{User: &tma.User{IsPremium: true}}
+		}
+		return &Input{User: &tma.User{}}
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h := Middleware(TMAPremium(), input, nil)(next)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?premium=1", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("allowed request: status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("denied request: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if body := rec.Body.String(); body != `{"rule":"tma_premium","reason":"Telegram Premium is required"}`+"\n" {
+		t.Errorf("denied request: body = %q", body)
+	}
+}
+
+func TestMiddleware_NilInput(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Middleware(nil input) did not panic")
+		}
+	}()
+	Middleware(TMAPremium(), nil, nil)
+}
+
+func BenchmarkCheck(b *testing.B) {
+	in := &Input{VK: &vkma.Params{
+		VkGroupID:         42,
+		VkViewerGroupRole: vkma.RoleAdmin,
+		VkPlatform:        vkma.MobileAndroid,
+	}}
+	rule := All(VKGroupAdmin(42), VKPlatform(vkma.MobileAndroid, vkma.MobileIPhone), DenyVKRef(vkma.Widget))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Check(rule, in)
+	}
+}
@@ -0,0 +1,278 @@
+// Package scheme verifies query strings signed in any of the ways the
+// supported platforms sign them, described as data instead of code.
+//
+// A Scheme combines a canonicalizer (which parameters are signed and how
+// they are written, see package canon), a key derivation step, a MAC or
+// digest algorithm and a signature encoding. The built-in schemes mirror
+// the tma, vkma, vkmashop and okapp packages; partner formats can be
+// declared with a Config and registered by name.
+package scheme
+
+import (
+	"hash"
+	"math"
+	"sync"
+
+	"github.com/elum-utils/sign/canon"
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// Scheme describes how a platform signs query parameters.
+//
+// The fields must not be changed once the scheme has been used.
+// A Scheme is safe for concurrent use.
+type Scheme struct {
+	// Name identifies the scheme in the registry, e.g. "vkma"
+	Name string
+
+	// SignKey is the parameter holding the signature, e.g. "sign"
+	SignKey string
+
+	// AppKey is the parameter selecting the secret in VerifyApp,
+	// e.g. "vk_app_id"; VerifyApp always fails if it is empty
+	AppKey string
+
+	// Filter selects the signed parameters; nil signs all of them.
+	// SignKey is never signed.
+	Filter canon.Filter
+
+	// Writer builds the canonical string from the sorted signed parameters
+	Writer canon.Writer
+
+	// Key derives the signing key from the secret; nil uses it as is
+	Key KeyFunc
+
+	// Algorithm computes the signature of the canonical string
+	Algorithm Algorithm
+
+	// Encoding is the text form of the signature in SignKey
+	Encoding Encoding
+
+	// Options controls query parsing, e.g. rejecting parameters without '='
+	Options canon.Options
+
+	// keys holds the derived key and hashers of the maxKeys most recently
+	// used secrets, created on first use
+	keysOnce sync.Once
+	keys     *utils.LRU[*keyed]
+}
+
+// maxKeys bounds the secrets a Scheme keeps derived keys for, so that
+// secrets taken from requests cannot grow it without limit. Secrets used
+// less recently are derived again on their next use.
+const maxKeys = 1024
+
+// keyed is the derived key of one secret and its pool of hashers.
+type keyed struct {
+	key  []byte
+	pool sync.Pool
+}
+
+// scratchSize fits a 64-byte sum and its hex encoding.
+const scratchSize = 256
+
+var scratchPool = sync.Pool{
+	New: func() any { return new([scratchSize]byte) },
+}
+
+// Verify validates the signature of rawQuery against secret.
+//
+// Parameters:
+//   - rawQuery: The raw URL query string, optionally starting with '?'
+//   - secret: The secret the signing key is derived from
+//
+// Returns:
+//   - canon.Pairs: The signed parameters sorted by key, if verification succeeds
+//   - bool: true if the signature is valid, false otherwise
+//
+// Parameters that are not signed are not returned, since they can be
+// changed by anyone. Failed verifications do not allocate once the
+// hashers for secret are pooled. Keys and hashers are kept for the 1024
+// most recently used secrets.
+func (s *Scheme) Verify(rawQuery, secret string) (canon.Pairs, bool) {
+	if secret == "" {
+		return nil, false
+	}
+	return s.verify(rawQuery, secret, nil)
+}
+
+// VerifyApp is like Verify, but selects the secret from secrets by the
+// value of the AppKey parameter.
+func (s *Scheme) VerifyApp(rawQuery string, secrets map[string]string) (canon.Pairs, bool) {
+	if len(secrets) == 0 || s.AppKey == "" {
+		return nil, false
+	}
+	return s.verify(rawQuery, "", secrets)
+}
+
+// Sign returns the encoded signature of the signed parameters of rawQuery,
+// ignoring any SignKey parameter already present. It returns false if
+// rawQuery cannot be parsed or the scheme is incomplete.
+func (s *Scheme) Sign(rawQuery, secret string) (string, bool) {
+	if secret == "" || !s.valid() {
+		return "", false
+	}
+
+	pairsPtr := utils.KVPool.Get().(*utils.KVSlice)
+	pairs := (*pairsPtr)[:0]
+	defer func() { utils.PutKV(pairsPtr, pairs) }()
+
+	tmpBufPtr := utils.TmpBufPool.Get().(*[]byte)
+	defer utils.TmpBufPool.Put(tmpBufPtr)
+
+	it := canon.NewIterator(rawQuery, (*tmpBufPtr)[:0], s.Options)
+	for p, ok := it.Next(); ok; p, ok = it.Next() {
+		if p.Key != s.SignKey && s.signed(p.Key) {
+			pairs = append(pairs, p)
+		}
+	}
+	if it.Err() != nil {
+		return "", false
+	}
+	pairs.Sort()
+
+	scratch := scratchPool.Get().(*[scratchSize]byte)
+	defer scratchPool.Put(scratch)
+	return string(s.sum(pairs, secret, scratch)), true
+}
+
+// verify implements Verify and VerifyApp. When secrets is not nil,
+// the secret is looked up by the AppKey parameter.
+func (s *Scheme) verify(rawQuery, secret string, secrets map[string]string) (canon.Pairs, bool) {
+	if !s.valid() {
+		return nil, false
+	}
+
+	var sig, appID string
+
+	// Get key-value pairs from sync.Pool to reduce allocations
+	pairsPtr := utils.KVPool.Get().(*utils.KVSlice)
+	pairs := (*pairsPtr)[:0] // Slice reset without reallocation
+	defer func() { utils.PutKV(pairsPtr, pairs) }()
+
+	// Get temporary buffer for URL unescaping from pool
+	tmpBufPtr := utils.TmpBufPool.Get().(*[]byte)
+	defer utils.TmpBufPool.Put(tmpBufPtr)
+
+	it := canon.NewIterator(rawQuery, (*tmpBufPtr)[:0], s.Options)
+	for p, ok := it.Next(); ok; p, ok = it.Next() {
+		if p.Key == s.SignKey {
+			sig = p.Value
+			continue
+		}
+		if p.Key == s.AppKey {
+			appID = p.Value
+		}
+		if s.signed(p.Key) {
+			pairs = append(pairs, p)
+		}
+	}
+	if it.Err() != nil {
+		return nil, false // Oversized input or invalid escapes
+	}
+
+	// Verify required parameters exist
+	if sig == "" {
+		return nil, false
+	}
+	if secrets != nil {
+		if secret = secrets[appID]; appID == "" || secret == "" {
+			return nil, false // Unknown application
+		}
+	}
+
+	// Sort parameters lexicographically by key
+	pairs.Sort()
+
+	scratch := scratchPool.Get().(*[scratchSize]byte)
+	valid := equal(s.sum(pairs, secret, scratch), sig, s.Encoding.IgnoreCase)
+	scratchPool.Put(scratch)
+	if !valid {
+		return nil, false
+	}
+
+	// Copy the signed parameters out of the pooled buffers
+	d := utils.NewDetacher(*tmpBufPtr, it.Buffer())
+	out := make(canon.Pairs, len(pairs))
+	for i, p := range pairs {
+		out[i] = canon.Pair{Key: d.String(p.Key), Value: d.String(p.Value)}
+	}
+	return out, true
+}
+
+// valid reports whether the scheme has everything needed to sign.
+func (s *Scheme) valid() bool {
+	return s.SignKey != "" && s.Writer != nil && s.Algorithm.New != nil && s.Encoding.Encode != nil
+}
+
+// signed reports whether key is covered by the signature.
+func (s *Scheme) signed(key string) bool {
+	return s.Filter == nil || s.Filter(key)
+}
+
+// sum computes the encoded signature of the sorted pairs into scratch.
+// The result is valid until scratch is returned to its pool.
+func (s *Scheme) sum(pairs canon.Pairs, secret string, scratch *[scratchSize]byte) []byte {
+	k := s.keyed(secret)
+
+	// Build canonical string for signing
+	bufPtr := utils.BufCanonicalPool.Get().(*[]byte)
+	buf := s.Writer((*bufPtr)[:0], pairs)
+	if !s.Algorithm.Keyed {
+		buf = append(buf, k.key...) // Digests are taken over data + key
+	}
+
+	h := k.pool.Get().(hash.Hash)
+	h.Write(buf)
+	sum := h.Sum(scratch[: 0 : scratchSize/4])
+	h.Reset()
+	k.pool.Put(h)
+	utils.PutBuf(&utils.BufCanonicalPool, bufPtr, buf)
+
+	n := s.Encoding.EncodedLen(len(sum))
+	enc := scratch[scratchSize/4:]
+	if n > len(enc) {
+		enc = make([]byte, n) // Sums longer than 512 bits
+	}
+	enc = enc[:n]
+	s.Encoding.Encode(enc, sum)
+	return enc
+}
+
+// keyed returns the derived key and hasher pool for secret,
+// creating them on first use.
+func (s *Scheme) keyed(secret string) *keyed {
+	s.keysOnce.Do(func() { s.keys = utils.NewLRU[*keyed](maxKeys) })
+	if k, ok := s.keys.Get(secret, "", 0); ok {
+		return k
+	}
+
+	// Concurrent first uses of a secret may each derive the key;
+	// the last one added is kept.
+	k := &keyed{key: []byte(secret)}
+	if s.Key != nil {
+		k.key = s.Key(secret)
+	}
+	alg := s.Algorithm
+	k.pool.New = func() any { return alg.hasher(k.key) }
+
+	s.keys.Add(secret, "", k, math.MaxInt64)
+	return k
+}
+
+// equal compares an encoded signature with sig in constant time,
+// folding sig to lower case if ignoreCase is set.
+func equal(enc []byte, sig string, ignoreCase bool) bool {
+	if len(enc) != len(sig) {
+		return false
+	}
+	var v byte
+	for i := 0; i < len(enc); i++ {
+		c := sig[i]
+		if ignoreCase && c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		v |= enc[i] ^ c
+	}
+	return v == 0
+}
@@ -0,0 +1,92 @@
+package scheme
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// Algorithm computes a signature over the canonical string.
+type Algorithm struct {
+	// Name identifies the algorithm in a Config, e.g. "hmac-sha256"
+	Name string
+
+	// New returns the underlying hash function
+	New func() hash.Hash
+
+	// Keyed selects HMAC with the derived key. Otherwise the key is
+	// appended to the canonical string and the result is hashed.
+	Keyed bool
+}
+
+// Built-in algorithms.
+var (
+	HMACSHA1   = Algorithm{Name: "hmac-sha1", New: sha1.New, Keyed: true}
+	HMACSHA256 = Algorithm{Name: "hmac-sha256", New: sha256.New, Keyed: true}
+	HMACSHA512 = Algorithm{Name: "hmac-sha512", New: sha512.New, Keyed: true}
+	MD5        = Algorithm{Name: "md5", New: md5.New}
+	SHA1       = Algorithm{Name: "sha1", New: sha1.New}
+	SHA256     = Algorithm{Name: "sha256", New: sha256.New}
+)
+
+// hasher returns a new hash for the derived key.
+func (a Algorithm) hasher(key []byte) hash.Hash {
+	if a.Keyed {
+		return hmac.New(a.New, key)
+	}
+	return a.New()
+}
+
+// Encoding is the text form of a signature.
+type Encoding struct {
+	// Name identifies the encoding in a Config, e.g. "hex"
+	Name string
+
+	// EncodedLen returns the length of the encoding of n bytes
+	EncodedLen func(n int) int
+
+	// Encode writes the encoding of src to dst, which has EncodedLen bytes
+	Encode func(dst, src []byte)
+
+	// IgnoreCase accepts upper-case signatures for lower-case encodings
+	IgnoreCase bool
+}
+
+// Built-in encodings.
+var (
+	// Hex is lower-case hex, accepted in either case
+	Hex = Encoding{Name: "hex", EncodedLen: hex.EncodedLen, Encode: encodeHex, IgnoreCase: true}
+
+	// Base64 is standard base64 with padding
+	Base64 = Encoding{
+		Name:       "base64",
+		EncodedLen: base64.StdEncoding.EncodedLen,
+		Encode:     base64.StdEncoding.Encode,
+	}
+
+	// Base64URL is URL-safe base64 without padding
+	Base64URL = Encoding{
+		Name:       "base64url",
+		EncodedLen: base64.RawURLEncoding.EncodedLen,
+		Encode:     base64.RawURLEncoding.Encode,
+	}
+)
+
+func encodeHex(dst, src []byte) { hex.Encode(dst, src) }
+
+// KeyFunc derives the signing key from a secret. Derived keys are
+// computed once per secret and scheme.
+type KeyFunc func(secret string) []byte
+
+// WebAppDataKey derives the key of Telegram-style init data:
+// HMAC-SHA256 of the bot token keyed with "WebAppData".
+func WebAppDataKey(secret string) []byte {
+	return utils.WebAppDataKey(secret)
+}
@@ -0,0 +1,145 @@
+package scheme
+
+import (
+	"errors"
+
+	"github.com/elum-utils/sign/canon"
+)
+
+// Config declares a scheme by the names of its parts, so a partner's
+// format can be loaded from a configuration file.
+//
+// Example (JSON):
+//
+//	{
+//	  "name": "partner",
+//	  "sign_key": "signature",
+//	  "app_key": "partner_id",
+//	  "prefix": "p_",
+//	  "canonical": "query",
+//	  "algorithm": "hmac-sha1",
+//	  "encoding": "base64"
+//	}
+type Config struct {
+	// Name identifies the scheme in the registry
+	Name string `json:"name"`
+
+	// SignKey is the parameter holding the signature
+	SignKey string `json:"sign_key"`
+
+	// AppKey is the parameter selecting the secret in VerifyApp
+	AppKey string `json:"app_key,omitempty"`
+
+	// Include, Exclude and Prefix select the signed parameters; all of
+	// the non-empty ones must accept a key. By default all are signed.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	Prefix  string   `json:"prefix,omitempty"`
+
+	// Canonical is the canonical string format: "lines", "query",
+	// "concat" (see the canon writers)
+	Canonical string `json:"canonical"`
+
+	// Key is the key derivation: "raw" (default) or "webappdata"
+	Key string `json:"key,omitempty"`
+
+	// Algorithm is one of "hmac-sha1", "hmac-sha256", "hmac-sha512",
+	// "md5", "sha1", "sha256"
+	Algorithm string `json:"algorithm"`
+
+	// Encoding is one of "hex", "base64", "base64url"
+	Encoding string `json:"encoding"`
+
+	// RejectBare rejects parameters without '='
+	RejectBare bool `json:"reject_bare,omitempty"`
+}
+
+// Errors returned by New for invalid configurations.
+var (
+	ErrNoName           = errors.New("scheme: missing name")
+	ErrNoSignKey        = errors.New("scheme: missing sign_key")
+	ErrUnknownCanonical = errors.New("scheme: unknown canonical format")
+	ErrUnknownKey       = errors.New("scheme: unknown key derivation")
+	ErrUnknownAlgorithm = errors.New("scheme: unknown algorithm")
+	ErrUnknownEncoding  = errors.New("scheme: unknown encoding")
+)
+
+var (
+	writers = map[string]canon.Writer{
+		"lines":  canon.AppendLines,
+		"query":  canon.AppendQuery,
+		"concat": canon.AppendConcat,
+	}
+	keyFuncs = map[string]KeyFunc{
+		"":           nil,
+		"raw":        nil,
+		"webappdata": WebAppDataKey,
+	}
+	algorithms = map[string]Algorithm{}
+	encodings  = map[string]Encoding{}
+)
+
+func init() {
+	for _, a := range []Algorithm{HMACSHA1, HMACSHA256, HMACSHA512, MD5, SHA1, SHA256} {
+		algorithms[a.Name] = a
+	}
+	for _, e := range []Encoding{Hex, Base64, Base64URL} {
+		encodings[e.Name] = e
+	}
+}
+
+// New builds the scheme declared by c.
+func New(c Config) (*Scheme, error) {
+	if c.Name == "" {
+		return nil, ErrNoName
+	}
+	if c.SignKey == "" {
+		return nil, ErrNoSignKey
+	}
+	writer, ok := writers[c.Canonical]
+	if !ok {
+		return nil, ErrUnknownCanonical
+	}
+	key, ok := keyFuncs[c.Key]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	alg, ok := algorithms[c.Algorithm]
+	if !ok {
+		return nil, ErrUnknownAlgorithm
+	}
+	enc, ok := encodings[c.Encoding]
+	if !ok {
+		return nil, ErrUnknownEncoding
+	}
+
+	var filters []canon.Filter
+	if len(c.Include) > 0 {
+		filters = append(filters, canon.Include(c.Include...))
+	}
+	if len(c.Exclude) > 0 {
+		filters = append(filters, canon.Exclude(c.Exclude...))
+	}
+	if c.Prefix != "" {
+		filters = append(filters, canon.Prefix(c.Prefix))
+	}
+
+	s := &Scheme{
+		Name:      c.Name,
+		SignKey:   c.SignKey,
+		AppKey:    c.AppKey,
+		Writer:    writer,
+		Key:       key,
+		Algorithm: alg,
+		Encoding:  enc,
+		Options:   canon.Options{RejectBare: c.RejectBare},
+	}
+	switch len(filters) {
+	case 0:
+	case 1:
+		s.Filter = filters[0]
+	default:
+		s.Filter = canon.And(filters...)
+	}
+	return s, nil
+}
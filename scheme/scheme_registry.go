@@ -0,0 +1,109 @@
+package scheme
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/elum-utils/sign/canon"
+)
+
+// Built-in schemes, equivalent to the signature checks of the platform
+// packages of the same names.
+var (
+	// TMA is Telegram Mini Apps init data: "key=value" lines signed with
+	// HMAC-SHA256 under the WebAppData key of the bot token, hex encoded
+	TMA = &Scheme{
+		Name:      "tma",
+		SignKey:   "hash",
+		Writer:    canon.AppendLines,
+		Key:       WebAppDataKey,
+		Algorithm: HMACSHA256,
+		Encoding:  Hex,
+		Options:   canon.Options{RejectBare: true},
+	}
+
+	// VKMA is VK Mini Apps launch parameters: the vk_* parameters as a
+	// query string, HMAC-SHA256, base64url without padding
+	VKMA = &Scheme{
+		Name:      "vkma",
+		SignKey:   "sign",
+		AppKey:    "vk_app_id",
+		Filter:    canon.Prefix("vk_"),
+		Writer:    canon.AppendQuery,
+		Algorithm: HMACSHA256,
+		Encoding:  Base64URL,
+	}
+
+	// VKMAShop is VK Shop payment notifications: MD5 of the concatenated
+	// "key=value" pairs followed by the secret, hex encoded
+	VKMAShop = &Scheme{
+		Name:      "vkmashop",
+		SignKey:   "sig",
+		AppKey:    "app_id",
+		Writer:    canon.AppendConcat,
+		Algorithm: MD5,
+		Encoding:  Hex,
+	}
+
+	// OKApp is OK application launch parameters, signed like VKMAShop.
+	// auth_sig is signed as a parameter but not checked on its own.
+	OKApp = &Scheme{
+		Name:      "okapp",
+		SignKey:   "sig",
+		AppKey:    "application_key",
+		Writer:    canon.AppendConcat,
+		Algorithm: MD5,
+		Encoding:  Hex,
+	}
+)
+
+var registry = struct {
+	mu      sync.RWMutex
+	schemes map[string]*Scheme
+}{schemes: map[string]*Scheme{}}
+
+func init() {
+	for _, s := range []*Scheme{TMA, VKMA, VKMAShop, OKApp} {
+		Register(s)
+	}
+}
+
+// Register adds s under its name, matched case-insensitively. It returns
+// false if the name is empty or already registered.
+func Register(s *Scheme) bool {
+	name := strings.ToLower(s.Name)
+	if name == "" {
+		return false
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.schemes[name]; ok {
+		return false
+	}
+	registry.schemes[name] = s
+	return true
+}
+
+// Lookup returns the scheme registered under name.
+func Lookup(name string) (*Scheme, bool) {
+	registry.mu.RLock()
+	s, ok := registry.schemes[strings.ToLower(name)]
+	registry.mu.RUnlock()
+	return s, ok
+}
+
+// Names returns the registered scheme names in sorted order.
+func Names() []string {
+	registry.mu.RLock()
+	names := make([]string, 0, len(registry.schemes))
+	for name := range registry.schemes {
+		names = append(names, name)
+	}
+	registry.mu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,421 @@
+package scheme
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/elum-utils/sign/canon"
+	"github.com/elum-utils/sign/internal/race"
+	"github.com/elum-utils/sign/okapp"
+	"github.com/elum-utils/sign/tma"
+	"github.com/elum-utils/sign/vkma"
+	"github.com/elum-utils/sign/vkmashop"
+)
+
+const (
+	tmaSecret = "1111111111:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	tmaQuery  = `user=%7B%22id%22%3A1093776793%2C%22first_name%22%3A%22%D0%90%D1%80%D1%82%D1%83%D1%80%22%2C%22last_name%22%3A%22%D0%A4%D1%80%D0%B0%D0%BD%D0%BA%22%2C%22username%22%3A%22gmelum%22%2C%22language_code%22%3A%22ru%22%2C%22is_premium%22%3Atrue%2C%22allows_write_to_pm%22%3Atrue%7D&chat_instance=3411281046910109270&chat_type=private&auth_date=1710181745&hash=ef19060b40a2277fa4debd9c6ad9b37b1e7ac1b6f467e53c66ca6d8df2c3c168`
+
+	vkmaQuery     = "vk_user_id=494075&vk_app_id=6736218&vk_is_app_user=1&vk_are_notifications_enabled=1&vk_language=ru&vk_access_token_settings=&vk_platform=andr%26oid&sign=gAgvKPEe3wJiC9ZdT16XuZ65_KSH5WkGSeDp_CQofws"
+	vkmashopQuery = "app_id=52333469&item=Subscribtion_Item_NoAd30&lang=ru_RU&notification_type=get_item_test&order_id=2256399&receiver_id=262959639&user_id=262959639&sig=871447748e3803be83acb30dec37b5e5"
+	okappQuery    = "api_server=https%3A%2F%2Fapi.ok.ru%2F&application_key=CBAFGHJKLMNOPQRST&custom_args=ref%3Dad+1&logged_user_id=575426848451&session_key=-s-abc.def"
+)
+
+var (
+	vkmaSecrets     = map[string]string{"6736218": "wvl68m4dR1UpLrVRli"}
+	vkmashopSecrets = map[string]string{"52333469": "5STCdDl55VezBzYt0AUA"}
+	okappSecrets    = map[string]string{"CBAFGHJKLMNOPQRST": "SECRETKEY123"}
+)
+
+// tamper returns variants of a valid query that must fail verification.
+func tamper(raw string) []string {
+	return []string{
+		"",
+		raw[:len(raw)-1],
+		raw[:len(raw)-1] + "0",
+		"x=1&" + raw,
+		raw + "&x=%zz",
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		scheme *Scheme
+		query  string
+		verify func(raw string) (canon.Pairs, bool)
+		oracle func(raw string) bool
+	}{
+		{
+			name:   "tma",
+			scheme: TMA,
+			query:  tmaQuery,
+			verify: func(raw string) (canon.Pairs, bool) { return TMA.Verify(raw, tmaSecret) },
+			oracle: func(raw string) bool { _, ok := tma.Verify(raw, tmaSecret); return ok },
+		},
+		{
+			name:   "vkma",
+			scheme: VKMA,
+			query:  vkmaQuery,
+			verify: func(raw string) (canon.Pairs, bool) { return VKMA.VerifyApp(raw, vkmaSecrets) },
+			oracle: func(raw string) bool { _, ok := vkma.Verify(raw, vkmaSecrets); return ok },
+		},
+		{
+			name:   "vkmashop",
+			scheme: VKMAShop,
+			query:  vkmashopQuery,
+			verify: func(raw string) (canon.Pairs, bool) { return VKMAShop.VerifyApp(raw, vkmashopSecrets) },
+			oracle: func(raw string) bool { _, ok := vkmashop.Verify(raw, vkmashopSecrets); return ok },
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if s, ok := Lookup(tt.name); !ok || s != tt.scheme {
+				t.Fatalf("Lookup(%q) = %p, %v; want %p", tt.name, s, ok, tt.scheme)
+			}
+			if _, ok := tt.verify(tt.query); !ok || !tt.oracle(tt.query) {
+				t.Fatalf("valid query rejected: scheme %v", ok)
+			}
+			for _, raw := range tamper(tt.query) {
+				if _, ok := tt.verify(raw); ok != tt.oracle(raw) {
+					t.Errorf("Verify(%q) = %v, platform package says %v", raw, ok, !ok)
+				}
+			}
+		})
+	}
+}
+
+func TestSign_MatchesPlatform(t *testing.T) {
+	t.Parallel()
+
+	sig, ok := OKApp.Sign(okappQuery, okappSecrets["CBAFGHJKLMNOPQRST"])
+	if !ok {
+		t.Fatal("Sign() = false")
+	}
+	raw := okappQuery + "&sig=" + sig
+	if _, ok := okapp.Verify(raw, okappSecrets); !ok {
+		t.Fatalf("okapp.Verify rejects the scheme signature %s", sig)
+	}
+
+	pairs, ok := OKApp.VerifyApp(raw, okappSecrets)
+	if !ok {
+		t.Fatal("VerifyApp() = false")
+	}
+	if v, _ := pairs.Get("custom_args"); v != "ref=ad 1" {
+		t.Errorf("custom_args = %q, want %q", v, "ref=ad 1")
+	}
+	if _, ok := pairs.Get("sig"); ok {
+		t.Error("signature returned among signed parameters")
+	}
+
+	// The signature of a signed query ignores the existing sign parameter
+	if again, _ := VKMA.Sign(vkmaQuery, vkmaSecrets["6736218"]); again != "gAgvKPEe3wJiC9ZdT16XuZ65_KSH5WkGSeDp_CQofws" {
+		t.Errorf("VKMA.Sign() = %q", again)
+	}
+}
+
+// randomQuery returns a query with n random parameters after the given
+// ones, drawn from keys so that some repeat, with values that need escaping.
+func randomQuery(r *rand.Rand, n int, keys []string, fixed ...string) string {
+	const chars = "aZ09-_.~ +&=%/?#\"'привет"
+	params := append([]string(nil), fixed...)
+	for i := 0; i < n; i++ {
+		v := []rune(chars)
+		val := make([]rune, r.Intn(8))
+		for j := range val {
+			val[j] = v[r.Intn(len(v))]
+		}
+		esc := url.QueryEscape(string(val))
+		if r.Intn(2) == 0 {
+			// %20 for spaces and a literal '+', which decodes to a space
+			esc = strings.NewReplacer("&", "%26", "=", "%3D").Replace(url.PathEscape(string(val)))
+		}
+		params = append(params, keys[r.Intn(len(keys))]+"="+esc)
+	}
+	r.Shuffle(len(params), func(i, j int) { params[i], params[j] = params[j], params[i] })
+	return strings.Join(params, "&")
+}
+
+func TestSign_CrossCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		scheme *Scheme
+		secret string
+		keys   []string
+		fixed  []string
+		verify func(raw string) bool
+	}{
+		{
+			name:   "tma",
+			scheme: TMA,
+			secret: tmaSecret,
+			keys:   []string{"user", "auth_date", "chat_type", "start_param", "query_id", "x"},
+			verify: func(raw string) bool { _, ok := tma.Verify(raw, tmaSecret); return ok },
+		},
+		{
+			name:   "vkma",
+			scheme: VKMA,
+			secret: vkmaSecrets["6736218"],
+			keys:   []string{"vk_user_id", "vk_platform", "vk_ts", "vk_ref", "vk_a", "vk_", "utm", "x"},
+			fixed:  []string{"vk_app_id=6736218"},
+			verify: func(raw string) bool { _, ok := vkma.Verify(raw, vkmaSecrets); return ok },
+		},
+		{
+			name:   "vkmashop",
+			scheme: VKMAShop,
+			secret: vkmashopSecrets["52333469"],
+			keys:   []string{"item", "lang", "user_id", "order_id", "status", "x"},
+			fixed:  []string{"app_id=52333469"},
+			verify: func(raw string) bool { _, ok := vkmashop.Verify(raw, vkmashopSecrets); return ok },
+		},
+		{
+			name:   "okapp",
+			scheme: OKApp,
+			secret: okappSecrets["CBAFGHJKLMNOPQRST"],
+			keys:   []string{"custom_args", "logged_user_id", "session_key", "api_server", "x"},
+			fixed:  []string{"application_key=CBAFGHJKLMNOPQRST"},
+			verify: func(raw string) bool { _, ok := okapp.Verify(raw, okappSecrets); return ok },
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < 200; i++ {
+				// Up to 21 parameters, past the insertion sort threshold
+				query := randomQuery(r, 1+r.Intn(20), tt.keys, tt.fixed...)
+				sig, ok := tt.scheme.Sign(query, tt.secret)
+				if !ok {
+					t.Fatalf("Sign(%q) = false", query)
+				}
+
+				raw := query + "&" + tt.scheme.SignKey + "=" + sig
+				if !tt.verify(raw) {
+					t.Fatalf("platform package rejects the scheme signature of %q", raw)
+				}
+				// The valid query, tampered ones, and an extra parameter,
+				// which breaks the signature unless it is unsigned
+				variants := append(tamper(raw), raw, raw+"&"+tt.keys[r.Intn(len(tt.keys))]+"=1")
+				for _, v := range variants {
+					var ok bool
+					if tt.scheme.AppKey != "" {
+						_, ok = tt.scheme.VerifyApp(v, map[string]string{appID(tt.fixed): tt.secret})
+					} else {
+						_, ok = tt.scheme.Verify(v, tt.secret)
+					}
+					if want := tt.verify(v); ok != want {
+						t.Errorf("scheme verifies %q as %v, platform package as %v", v, ok, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// appID returns the value of the first fixed parameter, the app ID.
+func appID(fixed []string) string {
+	if len(fixed) == 0 {
+		return ""
+	}
+	_, v, _ := strings.Cut(fixed[0], "=")
+	return v
+}
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(Config{
+		Name:      "partner",
+		SignKey:   "signature",
+		AppKey:    "p_id",
+		Prefix:    "p_",
+		Exclude:   []string{"p_debug"},
+		Canonical: "query",
+		Algorithm: "hmac-sha1",
+		Encoding:  "base64",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Signed independently with the standard library
+	const secret = "partner-secret"
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte("p_id=42&p_user=Jane%20Doe"))
+	sig := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	raw := "p_user=Jane+Doe&p_debug=1&other=x&p_id=42&signature=" + url.QueryEscape(sig)
+
+	pairs, ok := s.VerifyApp(raw, map[string]string{"42": secret})
+	if !ok {
+		t.Fatal("VerifyApp() = false, want true")
+	}
+	want := canon.Pairs{{Key: "p_id", Value: "42"}, {Key: "p_user", Value: "Jane Doe"}}
+	if len(pairs) != len(want) || pairs[0] != want[0] || pairs[1] != want[1] {
+		t.Errorf("pairs = %v, want %v", pairs, want)
+	}
+
+	if _, ok := s.VerifyApp(raw, map[string]string{"43": secret}); ok {
+		t.Error("VerifyApp() with unknown app = true")
+	}
+	if _, ok := s.Verify(raw+"&p_more=1", secret); ok {
+		t.Error("Verify() with an added signed parameter = true")
+	}
+	if _, ok := s.Verify(raw+"&p_debug=2", secret); !ok {
+		t.Error("Verify() with a changed excluded parameter = false")
+	}
+}
+
+func TestConfig_Errors(t *testing.T) {
+	t.Parallel()
+
+	valid := Config{Name: "x", SignKey: "s", Canonical: "concat", Algorithm: "md5", Encoding: "hex"}
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   error
+	}{
+		{"name", func(c *Config) { c.Name = "" }, ErrNoName},
+		{"sign key", func(c *Config) { c.SignKey = "" }, ErrNoSignKey},
+		{"canonical", func(c *Config) { c.Canonical = "json" }, ErrUnknownCanonical},
+		{"key", func(c *Config) { c.Key = "md5" }, ErrUnknownKey},
+		{"algorithm", func(c *Config) { c.Algorithm = "crc32" }, ErrUnknownAlgorithm},
+		{"encoding", func(c *Config) { c.Encoding = "base32" }, ErrUnknownEncoding},
+	}
+	for _, tt := range tests {
+		c := valid
+		tt.modify(&c)
+		if _, err := New(c); !errors.Is(err, tt.want) {
+			t.Errorf("%s: New() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if _, err := New(valid); err != nil {
+		t.Errorf("New(valid) error = %v", err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	s := &Scheme{Name: "Test-Registry", SignKey: "h", Writer: canon.AppendLines, Algorithm: HMACSHA512, Encoding: Hex}
+	if !Register(s) {
+		t.Fatal("Register() = false")
+	}
+	if Register(s) || Register(&Scheme{}) {
+		t.Error("Register() of a duplicate or unnamed scheme = true")
+	}
+	if got, ok := Lookup("test-registry"); !ok || got != s {
+		t.Errorf("Lookup() = %p, %v; want %p", got, ok, s)
+	}
+
+	found := false
+	for _, name := range Names() {
+		found = found || name == "test-registry"
+	}
+	if !found {
+		t.Errorf("Names() = %v, missing test-registry", Names())
+	}
+}
+
+func TestVerify_ConcurrentKeys(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheme{Name: "c", SignKey: "h", Writer: canon.AppendLines, Algorithm: HMACSHA512, Encoding: Hex}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			secret := "secret" + strconv.Itoa(w%3)
+			for i := 0; i < 50; i++ {
+				raw := "a=" + strconv.Itoa(i) + "&b=" + strconv.Itoa(w)
+				sig, _ := s.Sign(raw, secret)
+
+				mac := hmac.New(sha512.New, []byte(secret))
+				mac.Write([]byte("a=" + strconv.Itoa(i) + "\nb=" + strconv.Itoa(w)))
+				if want := hex.EncodeToString(mac.Sum(nil)); sig != want {
+					t.Errorf("Sign() = %q, want %q", sig, want)
+					return
+				}
+				if _, ok := s.Verify(raw+"&h="+sig, secret); !ok {
+					t.Errorf("Verify(%q) = false", raw)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func TestVerify_Allocs(t *testing.T) {
+	if race.Enabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+
+	bad := vkmashopQuery[:len(vkmashopQuery)-1] + "0"
+	VKMAShop.VerifyApp(bad, vkmashopSecrets) // Warm up the pools
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = VKMAShop.VerifyApp(bad, vkmashopSecrets)
+	})
+	if allocs != 0 {
+		t.Errorf("failed VerifyApp() allocs = %v, want 0", allocs)
+	}
+}
+
+func TestVerify_KeysBounded(t *testing.T) {
+	s := &Scheme{Name: "k", SignKey: "h", Writer: canon.AppendLines, Algorithm: HMACSHA512, Encoding: Hex}
+
+	for i := 0; i < maxKeys+10; i++ {
+		s.Verify("a=1&h=00", "secret"+strconv.Itoa(i))
+	}
+	if _, _, _, n := s.keys.Stats(); n != maxKeys {
+		t.Errorf("keys kept = %d, want %d", n, maxKeys)
+	}
+
+	// An evicted secret is derived again
+	sig, _ := s.Sign("a=1", "secret0")
+	if _, ok := s.Verify("a=1&h="+sig, "secret0"); !ok {
+		t.Error("Verify() with an evicted secret = false, want true")
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	b.Run("vkma", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = VKMA.VerifyApp(vkmaQuery, vkmaSecrets)
+		}
+	})
+	b.Run("vkma package", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = vkma.Verify(vkmaQuery, vkmaSecrets)
+		}
+	})
+	b.Run("vkmashop", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = VKMAShop.VerifyApp(vkmashopQuery, vkmashopSecrets)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+// Package sign provides a platform-agnostic view of users verified by the
+// platform packages of this module.
+//
+// The platform packages that identify a user (tma, vkma, vkmashop, okapp,
+// maxma, yandexgames, fbsigned) build an Identity from their verified
+// parameters and provide a Verifier implementation. Packages verifying
+// webhooks or payments (okpay, vkcallback, tgwebhook, discord, wechatmp and
+// others) do not: their requests carry no user identity and are not checked
+// from a single credential string. This package depends on none of them, so
+// they can all import it.
+package sign
+
+// Platform identifies the platform an Identity comes from.
+type Platform string
+
+const (
+	Telegram    Platform = "telegram"
+	VK          Platform = "vk"
+	OK          Platform = "ok"
+	MAX         Platform = "max"
+	YandexGames Platform = "yandex_games"
+	Facebook    Platform = "facebook"
+)
+
+// Identity is a verified user, independent of the platform.
+// Fields the platform does not provide are left empty.
+type Identity struct {
+	// Platform is the platform that verified the user
+	Platform Platform `json:"platform" msgpack:"platform"`
+
+	// UserID is the platform user ID; numeric IDs are in decimal
+	UserID string `json:"user_id" msgpack:"user_id"`
+
+	// Name is the display name
+	Name string `json:"name" msgpack:"name"`
+
+	// Username is the public handle, without '@'
+	Username string `json:"username" msgpack:"username"`
+
+	// Language is the interface language code (e.g., "en", "ru")
+	Language string `json:"language" msgpack:"language"`
+
+	// AvatarURL is a link to the profile photo
+	AvatarURL string `json:"avatar_url" msgpack:"avatar_url"`
+
+	// Premium reports a paid platform subscription (Telegram Premium)
+	Premium bool `json:"premium" msgpack:"premium"`
+
+	// Source is the launch source: the start parameter or referral the app
+	// was opened with
+	Source string `json:"source" msgpack:"source"`
+}
+
+// Verifier verifies raw credentials of one platform. It is implemented by
+// the Verifier structs of the user-identifying platform packages.
+type Verifier interface {
+	// Scheme returns the credential scheme name, e.g. "tma" or "vk".
+	Scheme() string
+
+	// Verify checks raw credentials and returns the verified identity.
+	Verify(raw string) (*Identity, bool)
+}
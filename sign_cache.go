@@ -0,0 +1,17 @@
+package sign
+
+// CacheStats reports the counters of a verification cache.
+type CacheStats struct {
+	// Hits counts lookups answered from the cache
+	Hits uint64
+
+	// Misses counts lookups that had to verify the input
+	Misses uint64
+
+	// Expired counts entries dropped because they outlived their TTL
+	// or the freshness of their signed timestamp
+	Expired uint64
+
+	// Entries is the number of entries currently cached
+	Entries int
+}
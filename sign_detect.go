@@ -0,0 +1,192 @@
+package sign
+
+// Schemes of the platform packages. Detect reports these names, and each
+// platform package declares its Scheme with the matching constant.
+const (
+	SchemeTMA         = "tma"
+	SchemeMAX         = "max"
+	SchemeVK          = "vk"
+	SchemeVKShop      = "vkshop"
+	SchemeOK          = "ok"
+	SchemeYandexGames = "yandex"
+	SchemeFacebook    = "fb"
+)
+
+// Confidence is how well raw data matches the key signature of a scheme.
+type Confidence uint8
+
+const (
+	// None means no marker key of the scheme is present.
+	None Confidence = iota
+
+	// Low means some, but not all, required keys are present.
+	Low
+
+	// Medium means all required keys are present.
+	Medium
+
+	// High means all required keys and at least one supporting key are
+	// present.
+	High
+)
+
+// String returns the confidence name.
+func (c Confidence) String() string {
+	switch c {
+	case Low:
+		return "low"
+	case Medium:
+		return "medium"
+	case High:
+		return "high"
+	}
+	return "none"
+}
+
+// detectable lists the schemes Detect distinguishes, in tie-break order.
+var detectable = [...]string{SchemeTMA, SchemeMAX, SchemeVK, SchemeVKShop, SchemeOK}
+
+// Key bits recorded by the key scan.
+const (
+	keyHash uint32 = 1 << iota
+	keyAuthDate
+	keyWebAppCommon // query_id, user, chat, start_param
+	keyTMAOnly      // chat_instance, chat_type, signature, receiver, can_send_after
+	keyVKAppID
+	keySign
+	keyVKOther // any other vk_* key
+	keyAppID
+	keySig
+	keyShopOther // notification_type, order_id, item, item_id, user_id
+	keyApplicationKey
+	keyOKOther // logged_user_id, session_key, auth_sig, api_server, apiconnection
+)
+
+// Detection is the result of Detect.
+type Detection struct {
+	// Scheme is the best matching scheme, empty if none matches with at
+	// least Medium confidence
+	Scheme string
+
+	// Confidence is the confidence of Scheme
+	Confidence Confidence
+
+	// Ambiguous reports that another scheme matches with the same
+	// confidence (e.g. MAX and Telegram init data share their keys)
+	Ambiguous bool
+
+	scores [len(detectable)]Confidence
+}
+
+// ConfidenceOf returns the confidence of any detectable scheme.
+func (d *Detection) ConfidenceOf(scheme string) Confidence {
+	for i, s := range detectable {
+		if s == scheme {
+			return d.scores[i]
+		}
+	}
+	return None
+}
+
+// Detect classifies raw launch data by its key signature without
+// verifying it:
+//   - tma: hash and auth_date; chat_instance, chat_type, signature,
+//     receiver or can_send_after make it unambiguous
+//   - max: hash and auth_date, without Telegram-only keys
+//   - vk: vk_app_id and sign
+//   - vkshop: app_id and sig
+//   - ok: application_key and sig
+//
+// Keys are compared without unescaping. Detect does not allocate.
+func Detect(raw string) Detection {
+	if len(raw) > 0 && raw[0] == '?' {
+		raw = raw[1:]
+	}
+
+	var keys uint32
+	for len(raw) > 0 {
+		end := 0
+		for end < len(raw) && raw[end] != '&' {
+			end++
+		}
+		pair := raw[:end]
+		if end < len(raw) {
+			end++
+		}
+		raw = raw[end:]
+
+		key := pair
+		for i := 0; i < len(pair); i++ {
+			if pair[i] == '=' {
+				key = pair[:i]
+				break
+			}
+		}
+		keys |= keyBit(key)
+	}
+
+	var d Detection
+	d.scores[0] = score(keys, keyHash|keyAuthDate, keyWebAppCommon|keyTMAOnly)
+	if keys&keyTMAOnly == 0 {
+		d.scores[1] = score(keys, keyHash|keyAuthDate, keyWebAppCommon)
+	}
+	d.scores[2] = score(keys, keyVKAppID|keySign, keyVKOther)
+	d.scores[3] = score(keys, keyAppID|keySig, keyShopOther)
+	d.scores[4] = score(keys, keyApplicationKey|keySig, keyOKOther)
+
+	for i, c := range d.scores {
+		switch {
+		case c < Medium || c < d.Confidence:
+		case c == d.Confidence:
+			d.Ambiguous = true
+		default:
+			d.Scheme, d.Confidence, d.Ambiguous = detectable[i], c, false
+		}
+	}
+	return d
+}
+
+// score rates keys against the required and supporting keys of a scheme.
+func score(keys, required, supporting uint32) Confidence {
+	switch {
+	case keys&required == 0:
+		return None
+	case keys&required != required:
+		return Low
+	case keys&supporting == 0:
+		return Medium
+	}
+	return High
+}
+
+// keyBit maps a parameter name to its key bit.
+func keyBit(key string) uint32 {
+	switch key {
+	case "hash":
+		return keyHash
+	case "auth_date":
+		return keyAuthDate
+	case "query_id", "user", "chat", "start_param":
+		return keyWebAppCommon
+	case "chat_instance", "chat_type", "signature", "receiver", "can_send_after":
+		return keyTMAOnly
+	case "vk_app_id":
+		return keyVKAppID
+	case "sign":
+		return keySign
+	case "app_id":
+		return keyAppID
+	case "sig":
+		return keySig
+	case "notification_type", "order_id", "item", "item_id", "user_id":
+		return keyShopOther
+	case "application_key":
+		return keyApplicationKey
+	case "logged_user_id", "session_key", "auth_sig", "api_server", "apiconnection":
+		return keyOKOther
+	}
+	if len(key) > 3 && key[:3] == "vk_" {
+		return keyVKOther
+	}
+	return 0
+}
@@ -0,0 +1,100 @@
+package sign
+
+import (
+	"testing"
+)
+
+const (
+	testTMA    = "query_id=AAH&user=%7B%22id%22%3A1%7D&auth_date=1710181745&signature=abc&hash=ef19"
+	testWebApp = "query_id=AAH&user=%7B%22id%22%3A1%7D&auth_date=1733485316&hash=d5f2"
+	testVK     = "vk_access_token_settings=&vk_app_id=6736218&vk_user_id=494075&vk_platform=android&sign=gAgv"
+	testShop   = "app_id=52333469&item=x&lang=ru_RU&notification_type=get_item&user_id=1&sig=8714"
+	testOK     = "application_key=CBAF&logged_user_id=575426848451&session_key=-s&sig=eb04"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		wantScheme    string
+		wantConf      Confidence
+		wantAmbiguous bool
+	}{
+		{"Empty", "", "", None, false},
+		{"Unrelated", "a=1&b=2", "", None, false},
+		{"Only hash", "hash=abc&user=1", "", None, false},
+		{"Telegram", testTMA, SchemeTMA, High, false},
+		{"Telegram with question mark", "?" + testTMA, SchemeTMA, High, false},
+		{"Telegram or MAX", testWebApp, SchemeTMA, High, true},
+		{"Bare hash and auth_date", "auth_date=1&hash=2", SchemeTMA, Medium, true},
+		{"VK", testVK, SchemeVK, High, false},
+		{"VK without other vk keys", "vk_app_id=1&sign=x", SchemeVK, Medium, false},
+		{"VK Shop", testShop, SchemeVKShop, High, false},
+		{"OK", testOK, SchemeOK, High, false},
+		{"OK without sig", "application_key=CBAF&logged_user_id=1", "", None, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Detect(tt.raw)
+			if d.Scheme != tt.wantScheme || d.Confidence != tt.wantConf || d.Ambiguous != tt.wantAmbiguous {
+				t.Errorf("Detect() = %q %s ambiguous=%v, want %q %s ambiguous=%v",
+					d.Scheme, d.Confidence, d.Ambiguous, tt.wantScheme, tt.wantConf, tt.wantAmbiguous)
+			}
+		})
+	}
+
+	d := Detect("application_key=CBAF&logged_user_id=1")
+	if c := d.ConfidenceOf(SchemeOK); c != Low {
+		t.Errorf("ConfidenceOf(ok) = %s, want low", c)
+	}
+	if c := d.ConfidenceOf("unknown"); c != None {
+		t.Errorf("ConfidenceOf(unknown) = %s, want none", c)
+	}
+}
+
+func TestDetect_Allocs(t *testing.T) {
+	for _, raw := range []string{testTMA, testWebApp, testVK, testShop, testOK} {
+		if n := testing.AllocsPerRun(100, func() { _ = Detect(raw) }); n != 0 {
+			t.Errorf("Detect(%.20q) allocs = %v, want 0", raw, n)
+		}
+	}
+}
+
+func TestRegistry_VerifyAny(t *testing.T) {
+	r := NewRegistry(
+		testVerifier{SchemeTMA, testTMA},
+		testVerifier{SchemeMAX, testWebApp},
+		testVerifier{SchemeVK, testVK},
+	)
+
+	tests := []struct {
+		name       string
+		raw        string
+		wantScheme string
+		wantValid  bool
+	}{
+		{"Undetected", "a=1", "", false},
+		{"Telegram", testTMA, SchemeTMA, true},
+		{"Ambiguous resolved by signature", testWebApp, SchemeMAX, true},
+		{"VK", testVK, SchemeVK, true},
+		{"Not registered", testOK, "", false},
+		{"Verification fails", testVK + "x", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, scheme, ok := r.VerifyAny(tt.raw)
+			if ok != tt.wantValid || scheme != tt.wantScheme {
+				t.Errorf("VerifyAny() = %q, %v; want %q, %v", scheme, ok, tt.wantScheme, tt.wantValid)
+			}
+		})
+	}
+}
+
+func BenchmarkDetect(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Detect(testVK)
+	}
+}
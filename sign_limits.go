@@ -0,0 +1,50 @@
+package sign
+
+import (
+	"strings"
+)
+
+// Limits bounds the raw query strings accepted by the query-based
+// verifiers (tma, maxma, vkma, vkmashop, okapp, okpay). Input exceeding a
+// limit is rejected before it is decoded or hashed. A zero field disables
+// that limit.
+type Limits struct {
+	// MaxLength is the largest raw query length in bytes
+	MaxLength int
+
+	// MaxParams is the largest number of '&'-separated parameters
+	MaxParams int
+
+	// MaxKeyLength is the largest escaped key length in bytes
+	MaxKeyLength int
+
+	// MaxValueLength is the largest escaped value length in bytes
+	MaxValueLength int
+}
+
+// DefaultLimits are the limits applied by the verifiers when no Limits are
+// passed. They leave ample room for real launch data. Verifiers read them
+// without synchronization, so they must not be modified after the first
+// verification; pass a *Limits to the verifiers to use other limits.
+var DefaultLimits = Limits{
+	MaxLength:      16 << 10,
+	MaxParams:      64,
+	MaxKeyLength:   64,
+	MaxValueLength: 8 << 10,
+}
+
+// AllowsQuery reports whether rawQuery is within MaxLength and MaxParams.
+// It only counts separators, so it is cheap enough to run before parsing.
+func (l *Limits) AllowsQuery(rawQuery string) bool {
+	if l.MaxLength > 0 && len(rawQuery) > l.MaxLength {
+		return false
+	}
+	return l.MaxParams <= 0 || strings.Count(rawQuery, "&") < l.MaxParams
+}
+
+// AllowsPair reports whether an escaped key and value of the given
+// lengths are within MaxKeyLength and MaxValueLength.
+func (l *Limits) AllowsPair(keyLen, valLen int) bool {
+	return (l.MaxKeyLength <= 0 || keyLen <= l.MaxKeyLength) &&
+		(l.MaxValueLength <= 0 || valLen <= l.MaxValueLength)
+}
@@ -0,0 +1,113 @@
+package sign
+
+import (
+	"strings"
+	"sync"
+)
+
+// Registry routes credentials to verifiers by scheme.
+//
+// Credentials have the form "<scheme> <raw>", like an HTTP Authorization
+// header value (e.g. "tma query_id=...&hash=..."). Schemes are matched
+// case-insensitively. A Registry is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	verifiers map[string]Verifier
+}
+
+// NewRegistry creates a registry with the given verifiers.
+// It panics if two verifiers declare the same scheme.
+func NewRegistry(verifiers ...Verifier) *Registry {
+	r := &Registry{verifiers: make(map[string]Verifier, len(verifiers))}
+	for _, v := range verifiers {
+		if !r.Register(v) {
+			panic("sign: duplicate scheme " + v.Scheme())
+		}
+	}
+	return r
+}
+
+// Register adds v under its scheme. It returns false if the scheme is
+// empty or already registered.
+func (r *Registry) Register(v Verifier) bool {
+	scheme := strings.ToLower(v.Scheme())
+	if scheme == "" {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.verifiers == nil {
+		r.verifiers = make(map[string]Verifier)
+	}
+	if _, ok := r.verifiers[scheme]; ok {
+		return false
+	}
+	r.verifiers[scheme] = v
+	return true
+}
+
+// Verifier returns the verifier registered for scheme.
+func (r *Registry) Verifier(scheme string) (Verifier, bool) {
+	r.mu.RLock()
+	v, ok := r.verifiers[strings.ToLower(scheme)]
+	r.mu.RUnlock()
+	return v, ok
+}
+
+// Verify splits credentials into scheme and raw data and verifies the
+// raw data with the verifier registered for the scheme.
+//
+// Parameters:
+//   - credentials: "<scheme> <raw>", e.g. an Authorization header value
+//
+// Returns:
+//   - *Identity: The verified identity
+//   - bool: false if the scheme is unknown or verification fails
+func (r *Registry) Verify(credentials string) (*Identity, bool) {
+	scheme, raw, ok := strings.Cut(credentials, " ")
+	if !ok {
+		return nil, false
+	}
+	return r.VerifyScheme(scheme, strings.TrimLeft(raw, " "))
+}
+
+// VerifyScheme verifies raw with the verifier registered for scheme.
+func (r *Registry) VerifyScheme(scheme, raw string) (*Identity, bool) {
+	v, ok := r.Verifier(scheme)
+	if !ok || raw == "" {
+		return nil, false
+	}
+	return v.Verify(raw)
+}
+
+// VerifyAny detects the scheme of raw launch data with Detect and
+// verifies it with the registered verifier. When the detection is
+// ambiguous, every registered scheme with the top confidence is tried in
+// turn, so MAX and Telegram init data are told apart by their signature.
+//
+// Returns:
+//   - *Identity: The verified identity
+//   - string: The scheme that verified the data
+//   - bool: false if no scheme matches with at least Medium confidence or
+//     verification fails
+func (r *Registry) VerifyAny(raw string) (*Identity, string, bool) {
+	d := Detect(raw)
+	if d.Scheme == "" {
+		return nil, "", false
+	}
+
+	for i, scheme := range detectable {
+		if d.scores[i] != d.Confidence {
+			continue
+		}
+		if id, ok := r.VerifyScheme(scheme, raw); ok {
+			return id, scheme, true
+		}
+		if !d.Ambiguous {
+			break
+		}
+	}
+	return nil, "", false
+}
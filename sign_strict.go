@@ -0,0 +1,43 @@
+package sign
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Errors reported by the VerifyStrict functions of the platform packages.
+// A *ParamError wraps one of the parameter errors; use errors.Is to match.
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrMalformedPair    = errors.New("malformed parameter")
+	ErrDuplicateKey     = errors.New("duplicate key")
+	ErrUnknownKey       = errors.New("unknown key")
+	ErrInvalidNumber    = errors.New("invalid number")
+	ErrInvalidEnum      = errors.New("unknown enum value")
+	ErrTooLarge         = errors.New("input exceeds limits")
+)
+
+// StrictOptions configures strict parsing.
+type StrictOptions struct {
+	// RejectUnknownKeys rejects parameters the platform package does not
+	// know, instead of ignoring them
+	RejectUnknownKeys bool
+
+	// Limits bounds the input; nil means DefaultLimits
+	Limits *Limits
+}
+
+// ParamError describes a parameter rejected in strict mode.
+type ParamError struct {
+	Key   string
+	Value string
+	Err   error
+}
+
+// Error implements error.
+func (e *ParamError) Error() string {
+	return e.Key + "=" + strconv.Quote(e.Value) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the parameter error, e.g. ErrDuplicateKey.
+func (e *ParamError) Unwrap() error { return e.Err }
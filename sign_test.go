@@ -0,0 +1,83 @@
+package sign
+
+import (
+	"errors"
+	"testing"
+)
+
+type testVerifier struct {
+	scheme string
+	raw    string
+}
+
+func (v testVerifier) Scheme() string { return v.scheme }
+
+func (v testVerifier) Verify(raw string) (*Identity, bool) {
+	if raw != v.raw {
+		return nil, false
+	}
+	return &Identity{Platform: Platform(v.scheme), UserID: "1"}, true
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(testVerifier{"tma", "init-data"}, testVerifier{"vk", "vk_user_id=1"})
+
+	tests := []struct {
+		name         string
+		credentials  string
+		wantPlatform Platform
+		wantValid    bool
+	}{
+		{"Empty", "", "", false},
+		{"No raw data", "tma", "", false},
+		{"Empty raw data", "tma ", "", false},
+		{"Unknown scheme", "ok init-data", "", false},
+		{"Wrong data", "tma other", "", false},
+		{"Routes to tma", "tma init-data", "tma", true},
+		{"Routes to vk", "vk vk_user_id=1", "vk", true},
+		{"Case-insensitive scheme", "TMA init-data", "tma", true},
+		{"Extra spaces", "tma   init-data", "tma", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := r.Verify(tt.credentials)
+			if ok != tt.wantValid {
+				t.Errorf("Verify() = %v, want %v", ok, tt.wantValid)
+			}
+			if ok && id.Platform != tt.wantPlatform {
+				t.Errorf("Platform = %q, want %q", id.Platform, tt.wantPlatform)
+			}
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	var r Registry
+	if !r.Register(testVerifier{scheme: "tma"}) {
+		t.Error("Register() = false, want true")
+	}
+	if r.Register(testVerifier{scheme: "TMA"}) {
+		t.Error("Register() of duplicate scheme = true, want false")
+	}
+	if r.Register(testVerifier{}) {
+		t.Error("Register() of empty scheme = true, want false")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewRegistry() with duplicate schemes did not panic")
+		}
+	}()
+	NewRegistry(testVerifier{scheme: "vk"}, testVerifier{scheme: "vk"})
+}
+
+func TestParamError(t *testing.T) {
+	var err error = &ParamError{Key: "vk_user_id", Value: "12a", Err: ErrInvalidNumber}
+	if got, want := err.Error(), `vk_user_id="12a": invalid number`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, ErrInvalidNumber) {
+		t.Error("errors.Is(err, ErrInvalidNumber) = false, want true")
+	}
+}
@@ -0,0 +1,245 @@
+package tgpassport
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+
+	"github.com/elum-utils/sign/tgwebhook"
+)
+
+// ParsePrivateKey parses the bot's PEM-encoded RSA private key in PKCS #1
+// or PKCS #8 form.
+func ParsePrivateKey(pemData []byte) (*rsa.PrivateKey, bool) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, false
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, true
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, false
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	return rsaKey, ok
+}
+
+// Decrypt decrypts and verifies Telegram Passport data.
+//
+// Parameters:
+//   - pd: The passport_data of a message
+//   - key: The bot's private RSA key
+//   - nonce: The nonce passed in the Passport request; it must match
+//
+// Returns:
+//   - *Passport: The decrypted documents and file secrets
+//   - bool: false if any part fails to decrypt or verify, an element has
+//     no credentials, or the nonce does not match
+func Decrypt(pd *tgwebhook.PassportData, key *rsa.PrivateKey, nonce string) (*Passport, bool) {
+	if pd == nil || key == nil {
+		return nil, false
+	}
+
+	creds, ok := decryptCredentials(&pd.Credentials, key)
+	if !ok || creds.Nonce != nonce {
+		return nil, false
+	}
+
+	p := &Passport{Nonce: creds.Nonce}
+	for i := range pd.Data {
+		el := &pd.Data[i]
+
+		switch el.Type {
+		case TypePhoneNumber:
+			p.PhoneNumber = el.PhoneNumber
+			continue
+		case TypeEmail:
+			p.Email = el.Email
+			continue
+		}
+
+		sv := creds.SecureData[el.Type]
+		if sv == nil {
+			return nil, false
+		}
+
+		switch el.Type {
+		case TypePersonalDetails:
+			p.PersonalDetails = new(PersonalDetails)
+			ok = decryptData(el, sv, p.PersonalDetails)
+		case TypeAddress:
+			p.Address = new(ResidentialAddress)
+			ok = decryptData(el, sv, p.Address)
+		case TypePassport, TypeDriverLicense, TypeIdentityCard, TypeInternalPassport:
+			var doc *IDDocument
+			doc, ok = decryptIDDocument(el, sv)
+			switch el.Type {
+			case TypePassport:
+				p.Passport = doc
+			case TypeDriverLicense:
+				p.DriverLicense = doc
+			case TypeIdentityCard:
+				p.IdentityCard = doc
+			default:
+				p.InternalPassport = doc
+			}
+		case TypeUtilityBill, TypeBankStatement, TypeRentalAgreement,
+			TypePassportRegistration, TypeTemporaryRegistration:
+			doc := Document{Type: el.Type}
+			doc.Files, ok = files(el.Files, sv.Files)
+			if ok {
+				doc.Translation, ok = files(el.Translation, sv.Translation)
+			}
+			p.Documents = append(p.Documents, doc)
+		default:
+			ok = false
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return p, true
+}
+
+// Decrypt decrypts a downloaded Passport file and verifies its hash.
+func (s *FileSecret) Decrypt(content []byte) ([]byte, bool) {
+	return decrypt(content, s.FileHash, s.Secret)
+}
+
+// decryptCredentials decrypts the credentials secret with RSA-OAEP and the
+// credentials with it.
+func decryptCredentials(ec *tgwebhook.EncryptedCredentials, key *rsa.PrivateKey) (*credentials, bool) {
+	data, err1 := base64.StdEncoding.DecodeString(ec.Data)
+	hash, err2 := base64.StdEncoding.DecodeString(ec.Hash)
+	encSecret, err3 := base64.StdEncoding.DecodeString(ec.Secret)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, false
+	}
+
+	secret, err := rsa.DecryptOAEP(sha1.New(), nil, key, encSecret, nil)
+	if err != nil {
+		return nil, false
+	}
+
+	plain, ok := decrypt(data, hash, secret)
+	if !ok {
+		return nil, false
+	}
+
+	var creds credentials
+	if err := json.Unmarshal(plain, &creds); err != nil {
+		return nil, false
+	}
+	return &creds, true
+}
+
+// decryptData decrypts the data field of el into v.
+func decryptData(el *tgwebhook.EncryptedPassportElement, sv *secureValue, v any) bool {
+	if sv.Data == nil {
+		return false
+	}
+	data, err := base64.StdEncoding.DecodeString(el.Data)
+	if err != nil {
+		return false
+	}
+	plain, ok := decrypt(data, sv.Data.DataHash, sv.Data.Secret)
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(plain, v) == nil
+}
+
+// decryptIDDocument decrypts an identity document and attaches its files.
+func decryptIDDocument(el *tgwebhook.EncryptedPassportElement, sv *secureValue) (*IDDocument, bool) {
+	doc := &IDDocument{Type: el.Type}
+	if !decryptData(el, sv, doc) {
+		return nil, false
+	}
+
+	var ok bool
+	if doc.FrontSide, ok = file(el.FrontSide, sv.FrontSide); !ok {
+		return nil, false
+	}
+	if doc.ReverseSide, ok = file(el.ReverseSide, sv.ReverseSide); !ok {
+		return nil, false
+	}
+	if doc.Selfie, ok = file(el.Selfie, sv.Selfie); !ok {
+		return nil, false
+	}
+	if doc.Translation, ok = files(el.Translation, sv.Translation); !ok {
+		return nil, false
+	}
+	return doc, true
+}
+
+// file pairs an optional file with its credentials. A file without
+// credentials is rejected.
+func file(f *tgwebhook.PassportFile, fc *fileCredentials) (*File, bool) {
+	if f == nil {
+		return nil, true
+	}
+	if fc == nil {
+		return nil, false
+	}
+	return &File{PassportFile: *f, Secret: FileSecret{FileHash: fc.FileHash, Secret: fc.Secret}}, true
+}
+
+// files pairs files with their credentials by index.
+func files(fs []tgwebhook.PassportFile, fcs []fileCredentials) ([]File, bool) {
+	if len(fs) != len(fcs) {
+		return nil, false
+	}
+	if len(fs) == 0 {
+		return nil, true
+	}
+	out := make([]File, len(fs))
+	for i := range fs {
+		out[i] = File{PassportFile: fs[i], Secret: FileSecret{FileHash: fcs[i].FileHash, Secret: fcs[i].Secret}}
+	}
+	return out, true
+}
+
+// decrypt decrypts data with AES-256-CBC using the key and IV derived from
+// SHA-512(secret ++ hash), checks that SHA-256 of the result equals hash
+// and strips the padding, whose length is stored in the first byte.
+func decrypt(data, hash, secret []byte) ([]byte, bool) {
+	if len(hash) != sha256.Size || len(secret) == 0 ||
+		len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, false
+	}
+
+	h := sha512.New()
+	h.Write(secret)
+	h.Write(hash)
+	var sum [sha512.Size]byte
+	kiv := h.Sum(sum[:0])
+
+	block, err := aes.NewCipher(kiv[:32])
+	if err != nil {
+		return nil, false
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, kiv[32:48]).CryptBlocks(plain, data)
+
+	check := sha256.Sum256(plain)
+	if subtle.ConstantTimeCompare(check[:], hash) != 1 {
+		return nil, false
+	}
+
+	pad := int(plain[0])
+	if pad == 0 || pad > len(plain) {
+		return nil, false
+	}
+	return plain[pad:], true
+}
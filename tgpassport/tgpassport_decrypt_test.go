@@ -0,0 +1,193 @@
+package tgpassport
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"sync"
+	"testing"
+
+	"github.com/elum-utils/sign/tgwebhook"
+)
+
+var (
+	keyOnce sync.Once
+	testKey *rsa.PrivateKey
+)
+
+func botKey(t testing.TB) *rsa.PrivateKey {
+	keyOnce.Do(func() {
+		var err error
+		if testKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return testKey
+}
+
+// encrypt mirrors the client side: random padding of 32..47 bytes with its
+// length in the first byte, then AES-256-CBC with a key derived from a
+// random secret and the hash of the padded data.
+func encrypt(plain []byte) (data, hash, secret []byte) {
+	pad := 32 + (16-len(plain)%16)%16
+	padded := make([]byte, pad+len(plain))
+	_, _ = rand.Read(padded[:pad])
+	padded[0] = byte(pad)
+	copy(padded[pad:], plain)
+
+	sum := sha256.Sum256(padded)
+	hash = sum[:]
+	secret = make([]byte, 32)
+	_, _ = rand.Read(secret)
+
+	kiv := sha512.Sum512(append(append([]byte(nil), secret...), hash...))
+	block, _ := aes.NewCipher(kiv[:32])
+	data = make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, kiv[32:48]).CryptBlocks(data, padded)
+	return data, hash, secret
+}
+
+func b64(b []byte) string { return base64.StdEncoding.EncodeToString(b) }
+
+// testPassport builds passport_data with personal details, a passport with
+// a front side, a utility bill, a phone number and an email. It also
+// returns the encrypted front side file.
+func testPassport(t *testing.T, nonce string) (*tgwebhook.PassportData, []byte) {
+	pdData, pdHash, pdSecret := encrypt([]byte(`{"first_name":"Ann","last_name":"Lee","birth_date":"01.02.1990","country_code":"GB"}`))
+	ppData, ppHash, ppSecret := encrypt([]byte(`{"document_no":"AB123","expiry_date":"01.01.2030"}`))
+	frontFile, frontHash, frontSecret := encrypt([]byte("jpeg bytes"))
+	billHash, billSecret := []byte("bill-hash-0123456789abcdef012345"), []byte("bill-secret")
+
+	creds, _ := json.Marshal(map[string]any{
+		"secure_data": map[string]any{
+			"personal_details": map[string]any{"data": map[string]any{"data_hash": pdHash, "secret": pdSecret}},
+			"passport": map[string]any{
+				"data":       map[string]any{"data_hash": ppHash, "secret": ppSecret},
+				"front_side": map[string]any{"file_hash": frontHash, "secret": frontSecret},
+			},
+			"utility_bill": map[string]any{"files": []any{map[string]any{"file_hash": billHash, "secret": billSecret}}},
+		},
+		"nonce": nonce,
+	})
+	credData, credHash, credSecret := encrypt(creds)
+	encSecret, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, &botKey(t).PublicKey, credSecret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &tgwebhook.PassportData{
+		Data: []tgwebhook.EncryptedPassportElement{
+			{Type: TypePersonalDetails, Data: b64(pdData)},
+			{Type: TypePassport, Data: b64(ppData), FrontSide: &tgwebhook.PassportFile{FileID: "front"}},
+			{Type: TypeUtilityBill, Files: []tgwebhook.PassportFile{{FileID: "bill"}}},
+			{Type: TypePhoneNumber, PhoneNumber: "447700900000"},
+			{Type: TypeEmail, Email: "ann@example.com"},
+		},
+		Credentials: tgwebhook.EncryptedCredentials{Data: b64(credData), Hash: b64(credHash), Secret: b64(encSecret)},
+	}, frontFile
+}
+
+func TestDecrypt(t *testing.T) {
+	pd, front := testPassport(t, "req-1")
+
+	p, ok := Decrypt(pd, botKey(t), "req-1")
+	if !ok {
+		t.Fatal("Decrypt() = false, want true")
+	}
+	if p.PersonalDetails == nil || p.PersonalDetails.FirstName != "Ann" || p.PersonalDetails.BirthDate != "01.02.1990" {
+		t.Errorf("PersonalDetails = %+v", p.PersonalDetails)
+	}
+	if p.Passport == nil || p.Passport.DocumentNo != "AB123" || p.Passport.FrontSide == nil || p.Passport.FrontSide.FileID != "front" {
+		t.Fatalf("Passport = %+v", p.Passport)
+	}
+	if len(p.Documents) != 1 || p.Documents[0].Type != TypeUtilityBill || p.Documents[0].Files[0].FileID != "bill" {
+		t.Errorf("Documents = %+v", p.Documents)
+	}
+	if p.PhoneNumber != "447700900000" || p.Email != "ann@example.com" {
+		t.Errorf("PhoneNumber, Email = %q, %q", p.PhoneNumber, p.Email)
+	}
+
+	content, ok := p.Passport.FrontSide.Secret.Decrypt(front)
+	if !ok || string(content) != "jpeg bytes" {
+		t.Errorf("FileSecret.Decrypt() = %q, %v", content, ok)
+	}
+	front[len(front)-1] ^= 1
+	if _, ok := p.Passport.FrontSide.Secret.Decrypt(front); ok {
+		t.Error("FileSecret.Decrypt() of tampered file = true, want false")
+	}
+}
+
+func TestDecrypt_Rejects(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(pd *tgwebhook.PassportData)
+		key    *rsa.PrivateKey
+		nonce  string
+	}{
+		{"Wrong nonce", func(pd *tgwebhook.PassportData) {}, nil, "req-2"},
+		{"Wrong key", func(pd *tgwebhook.PassportData) {}, otherKey, "req-1"},
+		{"Tampered credentials", func(pd *tgwebhook.PassportData) {
+			pd.Credentials.Data = pd.Credentials.Data[:len(pd.Credentials.Data)-8] + "AAAAAAA="
+		}, nil, "req-1"},
+		{"Tampered element", func(pd *tgwebhook.PassportData) {
+			d, _ := base64.StdEncoding.DecodeString(pd.Data[0].Data)
+			d[len(d)-1] ^= 1
+			pd.Data[0].Data = b64(d)
+		}, nil, "req-1"},
+		{"Swapped element data", func(pd *tgwebhook.PassportData) {
+			pd.Data[0].Data, pd.Data[1].Data = pd.Data[1].Data, pd.Data[0].Data
+		}, nil, "req-1"},
+		{"File without credentials", func(pd *tgwebhook.PassportData) {
+			pd.Data[1].ReverseSide = &tgwebhook.PassportFile{FileID: "back"}
+		}, nil, "req-1"},
+		{"Element without credentials", func(pd *tgwebhook.PassportData) {
+			pd.Data = append(pd.Data, tgwebhook.EncryptedPassportElement{Type: TypeAddress, Data: pd.Data[0].Data})
+		}, nil, "req-1"},
+		{"Unknown element type", func(pd *tgwebhook.PassportData) { pd.Data[2].Type = "selfie" }, nil, "req-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pd, _ := testPassport(t, "req-1")
+			tt.modify(pd)
+			key := tt.key
+			if key == nil {
+				key = botKey(t)
+			}
+			if _, ok := Decrypt(pd, key, tt.nonce); ok {
+				t.Error("Decrypt() = true, want false")
+			}
+		})
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key := botKey(t)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(key)
+
+	for name, block := range map[string]*pem.Block{
+		"PKCS1": {Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)},
+		"PKCS8": {Type: "PRIVATE KEY", Bytes: pkcs8},
+	} {
+		got, ok := ParsePrivateKey(pem.EncodeToMemory(block))
+		if !ok || !got.Equal(key) {
+			t.Errorf("ParsePrivateKey(%s) = %v", name, ok)
+		}
+	}
+	if _, ok := ParsePrivateKey([]byte("not a key")); ok {
+		t.Error("ParsePrivateKey(garbage) = true, want false")
+	}
+}
@@ -0,0 +1,136 @@
+// Package tgpassport decrypts and verifies Telegram Passport data received
+// by a bot.
+//
+// The credentials secret is encrypted with the bot's public RSA key
+// (RSA-OAEP with SHA-1). Every encrypted payload is decrypted with
+// AES-256-CBC using a key and IV derived from SHA-512(secret ++ hash), and
+// its SHA-256 must equal hash, so any tampered element is rejected.
+package tgpassport
+
+import (
+	"github.com/elum-utils/sign/tgwebhook"
+)
+
+// Element types of Telegram Passport.
+const (
+	TypePersonalDetails       = "personal_details"
+	TypePassport              = "passport"
+	TypeDriverLicense         = "driver_license"
+	TypeIdentityCard          = "identity_card"
+	TypeInternalPassport      = "internal_passport"
+	TypeAddress               = "address"
+	TypeUtilityBill           = "utility_bill"
+	TypeBankStatement         = "bank_statement"
+	TypeRentalAgreement       = "rental_agreement"
+	TypePassportRegistration  = "passport_registration"
+	TypeTemporaryRegistration = "temporary_registration"
+	TypePhoneNumber           = "phone_number"
+	TypeEmail                 = "email"
+)
+
+// PersonalDetails is the decrypted data of a personal_details element.
+type PersonalDetails struct {
+	FirstName            string `json:"first_name"`
+	LastName             string `json:"last_name"`
+	MiddleName           string `json:"middle_name"`
+	BirthDate            string `json:"birth_date"` // DD.MM.YYYY
+	Gender               string `json:"gender"`     // "male" or "female"
+	CountryCode          string `json:"country_code"`
+	ResidenceCountryCode string `json:"residence_country_code"`
+	FirstNameNative      string `json:"first_name_native"`
+	LastNameNative       string `json:"last_name_native"`
+	MiddleNameNative     string `json:"middle_name_native"`
+}
+
+// ResidentialAddress is the decrypted data of an address element.
+type ResidentialAddress struct {
+	StreetLine1 string `json:"street_line1"`
+	StreetLine2 string `json:"street_line2"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	CountryCode string `json:"country_code"`
+	PostCode    string `json:"post_code"`
+}
+
+// IDDocument is a decrypted passport, driver_license, identity_card or
+// internal_passport element.
+type IDDocument struct {
+	Type       string `json:"-"`
+	DocumentNo string `json:"document_no"`
+	ExpiryDate string `json:"expiry_date"` // DD.MM.YYYY, empty if none
+
+	FrontSide   *File  `json:"-"`
+	ReverseSide *File  `json:"-"`
+	Selfie      *File  `json:"-"`
+	Translation []File `json:"-"`
+}
+
+// Document is a utility_bill, bank_statement, rental_agreement,
+// passport_registration or temporary_registration element, which only
+// consists of files.
+type Document struct {
+	Type        string
+	Files       []File
+	Translation []File
+}
+
+// File is a Passport file together with the secret needed to decrypt it
+// after downloading it with getFile.
+type File struct {
+	tgwebhook.PassportFile
+	Secret FileSecret
+}
+
+// FileSecret holds the decryption secret and the hash of a file.
+type FileSecret struct {
+	FileHash []byte
+	Secret   []byte
+}
+
+// Passport is decrypted and verified Telegram Passport data.
+type Passport struct {
+	// Nonce is the nonce the bot passed in the Passport request
+	Nonce string
+
+	PersonalDetails  *PersonalDetails
+	Passport         *IDDocument
+	DriverLicense    *IDDocument
+	IdentityCard     *IDDocument
+	InternalPassport *IDDocument
+	Address          *ResidentialAddress
+
+	// Documents holds proof of address documents
+	Documents []Document
+
+	// PhoneNumber and Email are verified by Telegram and not encrypted
+	PhoneNumber string
+	Email       string
+}
+
+// credentials is the decrypted EncryptedCredentials data.
+type credentials struct {
+	SecureData map[string]*secureValue `json:"secure_data"`
+	Nonce      string                  `json:"nonce"`
+}
+
+// secureValue holds the secrets of one element.
+type secureValue struct {
+	Data        *dataCredentials  `json:"data"`
+	FrontSide   *fileCredentials  `json:"front_side"`
+	ReverseSide *fileCredentials  `json:"reverse_side"`
+	Selfie      *fileCredentials  `json:"selfie"`
+	Translation []fileCredentials `json:"translation"`
+	Files       []fileCredentials `json:"files"`
+}
+
+// dataCredentials decrypt the data field of an element.
+type dataCredentials struct {
+	DataHash []byte `json:"data_hash"`
+	Secret   []byte `json:"secret"`
+}
+
+// fileCredentials decrypt a file of an element.
+type fileCredentials struct {
+	FileHash []byte `json:"file_hash"`
+	Secret   []byte `json:"secret"`
+}
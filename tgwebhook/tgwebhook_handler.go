@@ -0,0 +1,119 @@
+package tgwebhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Handler is an http.Handler serving webhooks of one or more bots.
+//
+// Every bot is registered with setWebhook using its own secret_token; the
+// matching bot name is passed to the callbacks. Updates are dispatched to
+// the most specific callback set for them, falling back to OnUpdate.
+// A callback returning an error makes the handler answer 500, so Telegram
+// redelivers the update.
+type Handler struct {
+	// Bots maps bot names to webhook secret tokens.
+	Bots map[string]string
+
+	// MaxBodySize limits the request body; zero means DefaultMaxBodySize.
+	MaxBodySize int64
+
+	// OnMessage handles new messages without Web App data, payments or
+	// Passport data.
+	OnMessage func(ctx context.Context, bot string, m *Message) error
+
+	// OnWebAppData handles messages sent by Mini Apps via sendData.
+	OnWebAppData func(ctx context.Context, bot string, m *Message) error
+
+	// OnPassportData handles messages with Telegram Passport data.
+	OnPassportData func(ctx context.Context, bot string, m *Message) error
+
+	// OnCallbackQuery handles inline keyboard button presses.
+	OnCallbackQuery func(ctx context.Context, bot string, q *CallbackQuery) error
+
+	// OnPreCheckoutQuery decides whether a payment may proceed. The answer
+	// is sent back in the webhook response as answerPreCheckoutQuery;
+	// errorMessage is shown to the user when ok is false.
+	// Without this callback every pre-checkout query is declined.
+	OnPreCheckoutQuery func(ctx context.Context, bot string, q *PreCheckoutQuery) (ok bool, errorMessage string)
+
+	// OnSuccessfulPayment handles service messages about completed payments.
+	OnSuccessfulPayment func(ctx context.Context, bot string, m *Message) error
+
+	// OnUpdate handles updates no other callback handled.
+	OnUpdate func(ctx context.Context, bot string, u *Update) error
+}
+
+// answerPreCheckoutQuery is the webhook reply answering a pre-checkout query.
+type answerPreCheckoutQuery struct {
+	Method             string `json:"method"`
+	PreCheckoutQueryID string `json:"pre_checkout_query_id"`
+	OK                 bool   `json:"ok"`
+	ErrorMessage       string `json:"error_message,omitempty"`
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bot, ok := Verify(r, h.Bots)
+	if !ok {
+		http.Error(w, "invalid secret token", http.StatusUnauthorized)
+		return
+	}
+
+	maxBodySize := h.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+	u, ok := Decode(r.Body, maxBodySize)
+	if !ok {
+		http.Error(w, "invalid update", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	if q := u.PreCheckoutQuery; q != nil {
+		answer := answerPreCheckoutQuery{
+			Method:             "answerPreCheckoutQuery",
+			PreCheckoutQueryID: q.ID,
+			ErrorMessage:       "Payments are not available",
+		}
+		if h.OnPreCheckoutQuery != nil {
+			answer.OK, answer.ErrorMessage = h.OnPreCheckoutQuery(ctx, bot, q)
+		}
+		if answer.OK {
+			answer.ErrorMessage = ""
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(answer)
+		return
+	}
+
+	if err := h.dispatch(ctx, bot, u); err != nil {
+		http.Error(w, "update not processed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// dispatch passes u to the most specific callback that is set.
+func (h *Handler) dispatch(ctx context.Context, bot string, u *Update) error {
+	switch {
+	case u.Message != nil && u.Message.SuccessfulPayment != nil && h.OnSuccessfulPayment != nil:
+		return h.OnSuccessfulPayment(ctx, bot, u.Message)
+	case u.Message != nil && u.Message.WebAppData != nil && h.OnWebAppData != nil:
+		return h.OnWebAppData(ctx, bot, u.Message)
+	case u.Message != nil && u.Message.PassportData != nil && h.OnPassportData != nil:
+		return h.OnPassportData(ctx, bot, u.Message)
+	case u.Message != nil && u.Message.SuccessfulPayment == nil && u.Message.WebAppData == nil &&
+		u.Message.PassportData == nil && h.OnMessage != nil:
+		return h.OnMessage(ctx, bot, u.Message)
+	case u.CallbackQuery != nil && h.OnCallbackQuery != nil:
+		return h.OnCallbackQuery(ctx, bot, u.CallbackQuery)
+	case h.OnUpdate != nil:
+		return h.OnUpdate(ctx, bot, u)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package tgwebhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testBots = map[string]string{
+	"shop":    "shop-secret-token",
+	"support": "support-secret-token",
+}
+
+func post(h http.Handler, token, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	if token != "" {
+		r.Header.Set(HeaderSecretToken, token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		bots    map[string]string
+		wantBot string
+		wantOK  bool
+	}{
+		{"Missing header", "", testBots, "", false},
+		{"No bots", "shop-secret-token", nil, "", false},
+		{"Unknown token", "other", testBots, "", false},
+		{"Prefix of a token", "shop-secret", testBots, "", false},
+		{"Shared token", "x", map[string]string{"a": "x", "b": "x"}, "", false},
+		{"First bot", "shop-secret-token", testBots, "shop", true},
+		{"Second bot", "support-secret-token", testBots, "support", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.token != "" {
+				r.Header.Set(HeaderSecretToken, tt.token)
+			}
+			bot, ok := Verify(r, tt.bots)
+			if ok != tt.wantOK || (ok && bot != tt.wantBot) {
+				t.Errorf("Verify() = %q, %v; want %q, %v", bot, ok, tt.wantBot, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var calls []string
+	h := &Handler{
+		Bots: testBots,
+		OnMessage: func(ctx context.Context, bot string, m *Message) error {
+			calls = append(calls, bot+":message:"+m.Text+":"+m.From.FirstName)
+			return nil
+		},
+		OnWebAppData: func(ctx context.Context, bot string, m *Message) error {
+			calls = append(calls, bot+":web_app_data:"+m.WebAppData.Data)
+			return nil
+		},
+		OnSuccessfulPayment: func(ctx context.Context, bot string, m *Message) error {
+			p := m.SuccessfulPayment
+			calls = append(calls, bot+":payment:"+p.Currency+":"+p.InvoicePayload)
+			return nil
+		},
+		OnPassportData: func(ctx context.Context, bot string, m *Message) error {
+			d := m.PassportData
+			calls = append(calls, bot+":passport:"+d.Data[0].Type+":"+d.Credentials.Secret)
+			return nil
+		},
+		OnCallbackQuery: func(ctx context.Context, bot string, q *CallbackQuery) error {
+			return errors.New("storage unavailable")
+		},
+		OnPreCheckoutQuery: func(ctx context.Context, bot string, q *PreCheckoutQuery) (bool, string) {
+			if q.Currency != "XTR" || q.TotalAmount != 50 {
+				return false, "Price changed"
+			}
+			return true, ""
+		},
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		body     string
+		wantCode int
+		wantBody string
+		wantCall string
+	}{
+		{
+			name:     "bad token",
+			token:    "nope",
+			body:     `{"update_id":1}`,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "invalid JSON",
+			token:    "shop-secret-token",
+			body:     `{"update_id":`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "message",
+			token:    "support-secret-token",
+			body:     `{"update_id":2,"message":{"message_id":1,"from":{"id":7,"first_name":"Ann"},"chat":{"id":7,"type":"private"},"date":1,"text":"hi"}}`,
+			wantCode: http.StatusOK,
+			wantCall: "support:message:hi:Ann",
+		},
+		{
+			name:     "web app data",
+			token:    "shop-secret-token",
+			body:     `{"update_id":3,"message":{"message_id":2,"chat":{"id":7,"type":"private"},"web_app_data":{"data":"{\"cart\":[1,2]}","button_text":"Shop"}}}`,
+			wantCode: http.StatusOK,
+			wantCall: `shop:web_app_data:{"cart":[1,2]}`,
+		},
+		{
+			name:     "successful payment",
+			token:    "shop-secret-token",
+			body:     `{"update_id":4,"message":{"message_id":3,"chat":{"id":7,"type":"private"},"successful_payment":{"currency":"XTR","total_amount":50,"invoice_payload":"order-1","telegram_payment_charge_id":"ch_1"}}}`,
+			wantCode: http.StatusOK,
+			wantCall: "shop:payment:XTR:order-1",
+		},
+		{
+			name:     "passport data",
+			token:    "support-secret-token",
+			body:     `{"update_id":9,"message":{"message_id":4,"chat":{"id":7,"type":"private"},"passport_data":{"data":[{"type":"email","email":"a@b.c","hash":"aGFzaA=="}],"credentials":{"data":"ZA==","hash":"aA==","secret":"cw=="}}}}`,
+			wantCode: http.StatusOK,
+			wantCall: "support:passport:email:cw==",
+		},
+		{
+			name:     "pre-checkout accepted",
+			token:    "shop-secret-token",
+			body:     `{"update_id":5,"pre_checkout_query":{"id":"q1","from":{"id":7},"currency":"XTR","total_amount":50,"invoice_payload":"order-1"}}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"method":"answerPreCheckoutQuery","pre_checkout_query_id":"q1","ok":true}` + "\n",
+		},
+		{
+			name:     "pre-checkout declined",
+			token:    "shop-secret-token",
+			body:     `{"update_id":6,"pre_checkout_query":{"id":"q2","from":{"id":7},"currency":"XTR","total_amount":49,"invoice_payload":"order-1"}}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"method":"answerPreCheckoutQuery","pre_checkout_query_id":"q2","ok":false,"error_message":"Price changed"}` + "\n",
+		},
+		{
+			name:     "callback error is retried",
+			token:    "shop-secret-token",
+			body:     `{"update_id":7,"callback_query":{"id":"c1","from":{"id":7},"data":"buy"}}`,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "unhandled update",
+			token:    "shop-secret-token",
+			body:     `{"update_id":8,"edited_message":{"message_id":1,"chat":{"id":7,"type":"private"}}}`,
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = calls[:0]
+			rec := post(h, tt.token, tt.body)
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if tt.wantCall != "" && (len(calls) != 1 || calls[0] != tt.wantCall) {
+				t.Errorf("calls = %q, want %q", calls, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestHandler_BodyLimit(t *testing.T) {
+	h := &Handler{Bots: testBots, MaxBodySize: 16}
+	rec := post(h, "shop-secret-token", `{"update_id":1,"message":{"text":"too long"}}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandler_PreCheckoutWithoutCallback(t *testing.T) {
+	h := &Handler{Bots: testBots}
+	rec := post(h, "shop-secret-token", `{"update_id":1,"pre_checkout_query":{"id":"q1","currency":"XTR","total_amount":1}}`)
+	want := `{"method":"answerPreCheckoutQuery","pre_checkout_query_id":"q1","ok":false,"error_message":"Payments are not available"}` + "\n"
+	if rec.Body.String() != want {
+		t.Errorf("body = %q, want %q", rec.Body.String(), want)
+	}
+}
@@ -0,0 +1,124 @@
+// Package tgwebhook provides types for Telegram Bot API updates delivered
+// to a webhook, covering messages, Web App data, Telegram Stars payments and
+// Telegram Passport data.
+package tgwebhook
+
+import (
+	"github.com/elum-utils/sign/tma"
+)
+
+// User is a Telegram user. It shares its schema with Mini App init data.
+type User = tma.User
+
+// Chat is the chat a message belongs to.
+type Chat struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"` // "private", "group", "supergroup" or "channel"
+	Title     string `json:"title"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// WebAppData is data sent from a Mini App opened by a keyboard button
+// through Telegram.WebApp.sendData.
+type WebAppData struct {
+	// Data is the payload passed to sendData. It is not signed by Telegram
+	// beyond the webhook itself and must be validated like any user input.
+	Data string `json:"data"`
+
+	// ButtonText is the text of the keyboard button that opened the app
+	ButtonText string `json:"button_text"`
+}
+
+// SuccessfulPayment describes a completed payment.
+// For Telegram Stars, Currency is "XTR" and TotalAmount is in stars.
+type SuccessfulPayment struct {
+	Currency                   string `json:"currency"`
+	TotalAmount                int    `json:"total_amount"`
+	InvoicePayload             string `json:"invoice_payload"`
+	SubscriptionExpirationDate int64  `json:"subscription_expiration_date"`
+	IsRecurring                bool   `json:"is_recurring"`
+	IsFirstRecurring           bool   `json:"is_first_recurring"`
+	TelegramPaymentChargeID    string `json:"telegram_payment_charge_id"`
+	ProviderPaymentChargeID    string `json:"provider_payment_charge_id"`
+}
+
+// PreCheckoutQuery is sent before a payment is completed and must be
+// answered within 10 seconds.
+type PreCheckoutQuery struct {
+	ID             string `json:"id"`
+	From           User   `json:"from"`
+	Currency       string `json:"currency"`
+	TotalAmount    int    `json:"total_amount"`
+	InvoicePayload string `json:"invoice_payload"`
+}
+
+// PassportFile is a file uploaded to Telegram Passport. Files are
+// encrypted; see the tgpassport package for decryption.
+type PassportFile struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileSize     int64  `json:"file_size"`
+	FileDate     int64  `json:"file_date"`
+}
+
+// EncryptedPassportElement is a document or other personal data shared
+// with the bot through Telegram Passport.
+type EncryptedPassportElement struct {
+	Type        string         `json:"type"`
+	Data        string         `json:"data"` // base64, encrypted
+	PhoneNumber string         `json:"phone_number"`
+	Email       string         `json:"email"`
+	Files       []PassportFile `json:"files"`
+	FrontSide   *PassportFile  `json:"front_side"`
+	ReverseSide *PassportFile  `json:"reverse_side"`
+	Selfie      *PassportFile  `json:"selfie"`
+	Translation []PassportFile `json:"translation"`
+	Hash        string         `json:"hash"`
+}
+
+// EncryptedCredentials holds the encrypted secrets needed to decrypt
+// EncryptedPassportElement data and files.
+type EncryptedCredentials struct {
+	Data   string `json:"data"`   // base64, encrypted with the secret
+	Hash   string `json:"hash"`   // base64, data hash and secret for data decryption
+	Secret string `json:"secret"` // base64, encrypted with the bot's public RSA key
+}
+
+// PassportData is Telegram Passport data shared with the bot by the user.
+type PassportData struct {
+	Data        []EncryptedPassportElement `json:"data"`
+	Credentials EncryptedCredentials       `json:"credentials"`
+}
+
+// Message is a Telegram message.
+type Message struct {
+	MessageID         int                `json:"message_id"`
+	From              *User              `json:"from"`
+	Chat              Chat               `json:"chat"`
+	Date              int64              `json:"date"`
+	Text              string             `json:"text"`
+	WebAppData        *WebAppData        `json:"web_app_data"`
+	SuccessfulPayment *SuccessfulPayment `json:"successful_payment"`
+	PassportData      *PassportData      `json:"passport_data"`
+}
+
+// CallbackQuery is sent when a user presses an inline keyboard button.
+type CallbackQuery struct {
+	ID           string   `json:"id"`
+	From         User     `json:"from"`
+	Message      *Message `json:"message"`
+	ChatInstance string   `json:"chat_instance"`
+	Data         string   `json:"data"`
+}
+
+// Update is an incoming webhook update. At most one of the optional
+// fields is set.
+type Update struct {
+	UpdateID         int               `json:"update_id"`
+	Message          *Message          `json:"message"`
+	EditedMessage    *Message          `json:"edited_message"`
+	CallbackQuery    *CallbackQuery    `json:"callback_query"`
+	PreCheckoutQuery *PreCheckoutQuery `json:"pre_checkout_query"`
+}
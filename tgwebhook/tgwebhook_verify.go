@@ -0,0 +1,64 @@
+// Package tgwebhook provides functionality for verifying Telegram Bot API
+// webhook requests by their X-Telegram-Bot-Api-Secret-Token header and
+// decoding the delivered updates.
+package tgwebhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+const (
+	// HeaderSecretToken carries the secret_token set with setWebhook.
+	HeaderSecretToken = "X-Telegram-Bot-Api-Secret-Token"
+
+	// DefaultMaxBodySize limits request bodies read by the Handler.
+	DefaultMaxBodySize = 1 << 20
+)
+
+// Verify matches the secret token header of r against the configured bots.
+//
+// Parameters:
+//   - r: The incoming webhook request
+//   - bots: A map of bot names to their webhook secret tokens
+//
+// Returns:
+//   - string: The name of the bot the request belongs to
+//   - bool: true if the token matches one of the bots
+//
+// Every configured secret is compared in constant time, so the response
+// time does not reveal which bot, if any, matched.
+func Verify(r *http.Request, bots map[string]string) (string, bool) {
+	token := r.Header.Get(HeaderSecretToken)
+	if token == "" || len(bots) == 0 {
+		return "", false
+	}
+
+	var bot string
+	found := 0
+	for name, secret := range bots {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1 {
+			bot = name
+			found++
+		}
+	}
+	// An ambiguous token shared by several bots is rejected
+	return bot, found == 1
+}
+
+// Decode reads an update from body, reading at most maxBodySize bytes.
+// Larger bodies are rejected.
+func Decode(body io.Reader, maxBodySize int64) (*Update, bool) {
+	data, err := io.ReadAll(io.LimitReader(body, maxBodySize+1))
+	if err != nil || int64(len(data)) > maxBodySize {
+		return nil, false
+	}
+
+	var u Update
+	if err := json.Unmarshal(data, &u); err != nil {
+		return nil, false
+	}
+	return &u, true
+}
@@ -0,0 +1,149 @@
+package stars
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elum-utils/sign/tgwebhook"
+)
+
+const testSecret = "stars-signing-secret"
+
+var testOrder = Order{
+	ID:       "ord_01HZX3",
+	UserID:   1093776793,
+	Amount:   250,
+	Product:  "gems_pack_l",
+	IssuedAt: time.Unix(1710181745, 0),
+}
+
+func TestPackUnpack(t *testing.T) {
+	payload, ok := Pack(testOrder, testSecret)
+	if !ok {
+		t.Fatal("Pack() = false, want true")
+	}
+	if len(payload) > MaxPayloadSize {
+		t.Fatalf("len(payload) = %d, exceeds %d", len(payload), MaxPayloadSize)
+	}
+
+	o, ok := Unpack(payload, testSecret)
+	if !ok {
+		t.Fatal("Unpack() = false, want true")
+	}
+	if o.ID != testOrder.ID || o.UserID != testOrder.UserID || o.Amount != testOrder.Amount ||
+		o.Product != testOrder.Product || !o.IssuedAt.Equal(testOrder.IssuedAt) {
+		t.Errorf("Unpack() = %+v, want %+v", o, testOrder)
+	}
+}
+
+func TestPack_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  Order
+		secret string
+	}{
+		{"Missing secret", testOrder, ""},
+		{"Zero user", Order{ID: "1", Amount: 1}, testSecret},
+		{"Zero amount", Order{ID: "1", UserID: 1}, testSecret},
+		{"Too long", Order{ID: strings.Repeat("x", 60), UserID: 1, Amount: 1, Product: strings.Repeat("y", 20)}, testSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Pack(tt.order, tt.secret); ok {
+				t.Error("Pack() = true, want false")
+			}
+		})
+	}
+}
+
+func TestUnpack_Invalid(t *testing.T) {
+	payload, _ := Pack(testOrder, testSecret)
+
+	// Flip one character in the signed part
+	tampered := []byte(payload)
+	if tampered[4] == 'A' {
+		tampered[4] = 'B'
+	} else {
+		tampered[4] = 'A'
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+		secret  string
+	}{
+		{"Empty payload", "", testSecret},
+		{"Wrong secret", payload, "other"},
+		{"Tampered", string(tampered), testSecret},
+		{"Truncated", payload[:len(payload)-2], testSecret},
+		{"Not base64", "!!!!", testSecret},
+		{"Too long", strings.Repeat("A", MaxPayloadSize+1), testSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Unpack(tt.payload, tt.secret); ok {
+				t.Error("Unpack() = true, want false")
+			}
+		})
+	}
+}
+
+func TestVerifyPreCheckout(t *testing.T) {
+	payload, _ := Pack(testOrder, testSecret)
+
+	tests := []struct {
+		name   string
+		query  *tgwebhook.PreCheckoutQuery
+		wantOK bool
+	}{
+		{"Nil query", nil, false},
+		{"Wrong currency", &tgwebhook.PreCheckoutQuery{From: tgwebhook.User{ID: 1093776793}, Currency: "USD", TotalAmount: 250, InvoicePayload: payload}, false},
+		{"Wrong amount", &tgwebhook.PreCheckoutQuery{From: tgwebhook.User{ID: 1093776793}, Currency: "XTR", TotalAmount: 1, InvoicePayload: payload}, false},
+		{"Wrong user", &tgwebhook.PreCheckoutQuery{From: tgwebhook.User{ID: 42}, Currency: "XTR", TotalAmount: 250, InvoicePayload: payload}, false},
+		{"Forged payload", &tgwebhook.PreCheckoutQuery{From: tgwebhook.User{ID: 1093776793}, Currency: "XTR", TotalAmount: 250, InvoicePayload: "order-1"}, false},
+		{"Valid", &tgwebhook.PreCheckoutQuery{From: tgwebhook.User{ID: 1093776793}, Currency: "XTR", TotalAmount: 250, InvoicePayload: payload}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, ok := VerifyPreCheckout(tt.query, testSecret)
+			if ok != tt.wantOK {
+				t.Fatalf("VerifyPreCheckout() = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && o.ID != testOrder.ID {
+				t.Errorf("order ID = %q, want %q", o.ID, testOrder.ID)
+			}
+		})
+	}
+}
+
+func TestVerifyPayment(t *testing.T) {
+	payload, _ := Pack(testOrder, testSecret)
+	m := &tgwebhook.Message{
+		From: &tgwebhook.User{ID: 1093776793},
+		SuccessfulPayment: &tgwebhook.SuccessfulPayment{
+			Currency:       "XTR",
+			TotalAmount:    250,
+			InvoicePayload: payload,
+		},
+	}
+
+	if _, ok := VerifyPayment(m, testSecret); !ok {
+		t.Error("VerifyPayment() = false, want true")
+	}
+	if _, ok := VerifyPayment(&tgwebhook.Message{From: m.From}, testSecret); ok {
+		t.Error("VerifyPayment() accepted a message without payment")
+	}
+}
+
+func BenchmarkUnpack(b *testing.B) {
+	payload, _ := Pack(testOrder, testSecret)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Unpack(payload, testSecret)
+	}
+}
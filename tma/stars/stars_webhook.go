@@ -0,0 +1,24 @@
+package stars
+
+import (
+	"github.com/elum-utils/sign/tgwebhook"
+)
+
+// VerifyPreCheckout checks a pre_checkout_query against its signed payload.
+// Use it in tgwebhook.Handler.OnPreCheckoutQuery before accepting the payment.
+func VerifyPreCheckout(q *tgwebhook.PreCheckoutQuery, secret string) (*Order, bool) {
+	if q == nil {
+		return nil, false
+	}
+	return Check(q.InvoicePayload, q.Currency, q.TotalAmount, int64(q.From.ID), secret)
+}
+
+// VerifyPayment checks the successful_payment of m against its signed payload.
+// The payer is taken from the sender of the service message.
+func VerifyPayment(m *tgwebhook.Message, secret string) (*Order, bool) {
+	if m == nil || m.SuccessfulPayment == nil || m.From == nil {
+		return nil, false
+	}
+	p := m.SuccessfulPayment
+	return Check(p.InvoicePayload, p.Currency, p.TotalAmount, int64(m.From.ID), secret)
+}
@@ -0,0 +1,39 @@
+package tma
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	raws := []string{bytesQuery, bytesQuery + "0", "", bytesQuery}
+	results := VerifyBatch(context.Background(), raws, bytesSecret, 2)
+	for i, r := range results {
+		valid := i == 0 || i == 3
+		if valid && (r.Err != nil || r.Params.ChatType != "private") {
+			t.Errorf("result %d = %+v, want valid", i, r)
+		}
+		if !valid && !errors.Is(r.Err, sign.ErrInvalidSignature) {
+			t.Errorf("result %d = %+v, want ErrInvalidSignature", i, r)
+		}
+	}
+
+	in := make(chan string, len(raws))
+	for _, raw := range raws {
+		in <- raw
+	}
+	close(in)
+	i := 0
+	for r := range VerifyStream(context.Background(), in, bytesSecret, 2) {
+		if (r.Err == nil) != (results[i].Err == nil) {
+			t.Errorf("stream result %d = %+v, want %+v", i, r, results[i])
+		}
+		i++
+	}
+	if i != len(raws) {
+		t.Errorf("received %d results, want %d", i, len(raws))
+	}
+}
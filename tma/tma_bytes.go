@@ -0,0 +1,26 @@
+package tma
+
+import (
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// VerifyBytes is like Verify but reads the init data straight from raw,
+// without copying it to a string first.
+//
+// String fields of the returned Params may point into raw and are only
+// valid while raw is not modified. Call Detach before raw is reused,
+// e.g. when it is a request buffer owned by the HTTP server.
+func VerifyBytes(raw []byte, secret string) (*Params, bool) {
+	return Verify(utils.BytesToString(raw), secret)
+}
+
+// Detach copies the string fields of p into memory owned by p, so that
+// they stay valid after the buffer passed to VerifyBytes is reused.
+// It allocates at most once, plus a new decode cache if p had one, since
+// values decoded before Detach may still point into the buffer.
+func (p *Params) Detach() {
+	utils.CloneStrings(&p.UserData, &p.ReceiverData, &p.ChatData, &p.ChatInstance, &p.ChatType, &p.StartParam, &p.Hash)
+	if p.cache != nil {
+		p.cache = new(cached)
+	}
+}
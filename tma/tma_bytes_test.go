@@ -0,0 +1,70 @@
+package tma
+
+import (
+	"testing"
+)
+
+const (
+	bytesSecret = "1111111111:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	bytesQuery  = `user=%7B%22id%22%3A1093776793%2C%22first_name%22%3A%22%D0%90%D1%80%D1%82%D1%83%D1%80%22%2C%22last_name%22%3A%22%D0%A4%D1%80%D0%B0%D0%BD%D0%BA%22%2C%22username%22%3A%22gmelum%22%2C%22language_code%22%3A%22ru%22%2C%22is_premium%22%3Atrue%2C%22allows_write_to_pm%22%3Atrue%7D&chat_instance=3411281046910109270&chat_type=private&auth_date=1710181745&hash=ef19060b40a2277fa4debd9c6ad9b37b1e7ac1b6f467e53c66ca6d8df2c3c168`
+)
+
+func TestVerifyBytes(t *testing.T) {
+	want, ok := Verify(bytesQuery, bytesSecret)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	raw := []byte(bytesQuery)
+	p, ok := VerifyBytes(raw, bytesSecret)
+	if !ok || !sameParams(p, want) {
+		t.Fatalf("VerifyBytes() = %+v, %v; want %+v, true", p, ok, want)
+	}
+
+	p.Detach()
+	for i := range raw {
+		raw[i] = 'x'
+	}
+	if !sameParams(p, want) {
+		t.Errorf("after Detach and reuse = %+v, want %+v", p, want)
+	}
+
+	raw[len(raw)-1] = '0'
+	if _, ok := VerifyBytes(raw, bytesSecret); ok {
+		t.Error("VerifyBytes() on tampered input = true, want false")
+	}
+	if _, ok := VerifyBytes(nil, bytesSecret); ok {
+		t.Error("VerifyBytes(nil) = true, want false")
+	}
+}
+
+func BenchmarkVerifyBytes(b *testing.B) {
+	raw := []byte(bytesQuery)
+
+	b.Run("string", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = Verify(string(raw), bytesSecret)
+		}
+	})
+	b.Run("bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = VerifyBytes(raw, bytesSecret)
+		}
+	})
+	b.Run("bytes+detach", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			p, _ := VerifyBytes(raw, bytesSecret)
+			p.Detach()
+		}
+	})
+}
+
+// sameParams compares the fields of a and b, ignoring their decode caches.
+func sameParams(a, b *Params) bool {
+	x, y := *a, *b
+	x.cache, y.cache = nil, nil
+	return x == y
+}
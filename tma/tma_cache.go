@@ -0,0 +1,96 @@
+package tma
+
+import (
+	"math"
+	"time"
+
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// now returns the current time; tests replace it.
+var now = time.Now
+
+// Cache remembers successful verifications, so init data that a client
+// sends with every API call is verified once per session.
+//
+// Entries are keyed by SHA-256 digests of the raw init data and the bot
+// token, and expire after the TTL or, if a max age is set, once auth_date
+// is older than it, whichever comes first. Failed verifications are not
+// cached. A Cache is safe for concurrent use.
+//
+// An entry takes about 270 bytes plus the strings of its Params, which are
+// at most as long as the raw init data, so a full cache is bounded by size
+// times the input limit.
+type Cache struct {
+	// Limits bounds the input of verifications that miss the cache; nil
+	// means sign.DefaultLimits. Set it before the first call to Verify
+	Limits *sign.Limits
+
+	lru    *utils.LRU[Params]
+	ttl    time.Duration
+	maxAge time.Duration
+}
+
+// NewCache creates a cache of at most size entries. Entries live for ttl
+// and no longer than maxAge after their auth_date; a non-positive ttl or
+// maxAge disables that bound.
+func NewCache(size int, ttl, maxAge time.Duration) *Cache {
+	return &Cache{lru: utils.NewLRU[Params](size), ttl: ttl, maxAge: maxAge}
+}
+
+// Verify is like Verify, but answers repeated init data from the cache.
+//
+// Every call returns a new *Params with its own lazily decoded user, so
+// callers never share mutable state through the cache. Like Verify, it does
+// not reject stale init data; check AuthDate as usual.
+func (c *Cache) Verify(rawQuery, secret string) (*Params, bool) {
+	t := now()
+	if p, ok := c.lru.Get(rawQuery, secret, t.UnixNano()); ok {
+		// Params holds only strings and values besides the decode cache,
+		// which newParams replaces; see TestCache_Isolation
+		return newParams(&p), true
+	}
+
+	params, ok := VerifyLimits(rawQuery, secret, c.Limits)
+	if !ok {
+		return nil, false
+	}
+
+	if expires, ok := c.expires(t, params.AuthDate); ok {
+		entry := *params
+		entry.cache = nil
+		c.lru.Add(rawQuery, secret, entry, expires)
+	}
+	return params, true
+}
+
+// expires returns when an entry verified at t with the given auth_date
+// leaves the cache, or false if it must not be cached.
+func (c *Cache) expires(t, authDate time.Time) (int64, bool) {
+	var exp time.Time // Zero: never expires
+	if c.ttl > 0 {
+		exp = t.Add(c.ttl)
+	}
+	if c.maxAge > 0 {
+		if authDate.IsZero() {
+			return 0, false
+		}
+		if e := authDate.Add(c.maxAge); exp.IsZero() || e.Before(exp) {
+			exp = e
+		}
+	}
+	if exp.IsZero() {
+		return math.MaxInt64, true
+	}
+	return exp.UnixNano(), exp.After(t)
+}
+
+// Stats returns the cache counters.
+func (c *Cache) Stats() sign.CacheStats {
+	hits, misses, expired, n := c.lru.Stats()
+	return sign.CacheStats{Hits: hits, Misses: misses, Expired: expired, Entries: n}
+}
+
+// Purge removes all entries, e.g. after rotating the bot token.
+func (c *Cache) Purge() { c.lru.Purge() }
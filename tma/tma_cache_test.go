@@ -0,0 +1,148 @@
+package tma
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestCache(t *testing.T) {
+	authDate := time.Unix(1710181745, 0)
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return authDate.Add(time.Minute) }
+
+	raw := signInitData(lazyToken, "user", `{"id":7,"first_name":"Ann"}`, "auth_date", strconv.FormatInt(authDate.Unix(), 10))
+	c := NewCache(2, time.Hour, 10*time.Minute)
+
+	p1, ok := c.Verify(raw, lazyToken)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	p2, ok := c.Verify(raw, lazyToken)
+	if !ok || p2 == p1 || !sameParams(p1, p2) {
+		t.Fatalf("cached Verify() = %+v, %v; want a copy of %+v", p2, ok, p1)
+	}
+	if got, want := c.Stats(), (sign.CacheStats{Hits: 1, Misses: 1, Entries: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	// Callers do not share state through the cache
+	u1, _ := p1.User()
+	u2, _ := p2.User()
+	if u1 == u2 {
+		t.Error("User() of two cache hits share a *User")
+	}
+	p2.ChatType = "changed"
+	if p3, _ := c.Verify(raw, lazyToken); p3.ChatType == "changed" {
+		t.Error("modifying a returned Params changed the cache")
+	}
+
+	if _, ok := c.Verify(raw, "other-token"); ok {
+		t.Error("Verify() with another token = true, want false")
+	}
+
+	// Entries expire with auth_date
+	now = func() time.Time { return authDate.Add(11 * time.Minute) }
+	if _, ok := c.Verify(raw, lazyToken); !ok {
+		t.Error("Verify() after expiry = false, want true")
+	}
+	if st := c.Stats(); st.Expired != 1 || st.Entries != 0 {
+		t.Errorf("Stats() after expiry = %+v, want 1 expired, 0 entries", st)
+	}
+}
+
+func TestCache_Isolation(t *testing.T) {
+	authDate := time.Unix(1710181745, 0)
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return authDate.Add(time.Minute) }
+
+	raw := signInitData(lazyToken, "user", `{"id":7,"first_name":"Ann"}`, "chat_type", "private", "auth_date", strconv.FormatInt(authDate.Unix(), 10))
+	want, _ := Verify(raw, lazyToken)
+	c := NewCache(2, time.Hour, 0)
+
+	// Modify the Params of a miss and of a hit, and their decoded users
+	for i := 0; i < 2; i++ {
+		p, ok := c.Verify(raw, lazyToken)
+		if !ok {
+			t.Fatalf("Verify() #%d = false, want true", i+1)
+		}
+		u, err := p.User()
+		if err != nil {
+			t.Fatal(err)
+		}
+		*u = User{ID: -1, FirstName: "changed"}
+		*p = Params{UserData: `{"id":-1}`, ChatType: "changed", AuthDate: time.Unix(1, 0), Hash: "changed"}
+	}
+
+	p, ok := c.Verify(raw, lazyToken)
+	if !ok || !sameParams(p, want) {
+		t.Fatalf("Verify() after modifications = %+v, %v; want %+v", p, ok, want)
+	}
+	if u, err := p.User(); err != nil || *u != (User{ID: 7, FirstName: "Ann"}) {
+		t.Errorf("User() after modifications = %+v, %v; want Ann", u, err)
+	}
+	if st := c.Stats(); st.Hits != 2 || st.Misses != 1 {
+		t.Errorf("Stats() = %+v, want 2 hits, 1 miss", st)
+	}
+}
+
+func TestCache_Bounds(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	start := time.Unix(1710181745, 0)
+	now = func() time.Time { return start }
+
+	raws := make([]string, 3)
+	for i := range raws {
+		raws[i] = signInitData(lazyToken, "user", `{"id":`+strconv.Itoa(i)+`}`)
+	}
+
+	c := NewCache(2, time.Minute, 0)
+	for _, raw := range raws {
+		if _, ok := c.Verify(raw, lazyToken); !ok {
+			t.Fatal("Verify() = false, want true")
+		}
+	}
+	if n := c.Stats().Entries; n != 2 {
+		t.Fatalf("Entries = %d, want 2", n)
+	}
+
+	// raws[0] was evicted, raws[2] is still cached
+	c.Verify(raws[2], lazyToken)
+	c.Verify(raws[0], lazyToken)
+	if st := c.Stats(); st.Hits != 1 || st.Misses != 4 {
+		t.Errorf("Stats() = %+v, want 1 hit, 4 misses", st)
+	}
+
+	// TTL expiry
+	now = func() time.Time { return start.Add(time.Minute) }
+	c.Verify(raws[0], lazyToken)
+	if st := c.Stats(); st.Expired != 1 {
+		t.Errorf("Expired = %d, want 1", st.Expired)
+	}
+
+	// Without auth_date nothing is cached when a max age is set
+	c = NewCache(2, 0, time.Minute)
+	c.Verify(raws[0], lazyToken)
+	if n := c.Stats().Entries; n != 0 {
+		t.Errorf("Entries without auth_date = %d, want 0", n)
+	}
+}
+
+func BenchmarkCache_Verify(b *testing.B) {
+	c := NewCache(1024, time.Hour, 0)
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = c.Verify(bytesQuery, bytesSecret)
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = Verify(bytesQuery, bytesSecret)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package tma
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/elum-utils/sign"
+)
+
+// Scheme is the credential scheme of Telegram Mini App init data,
+// as in the "Authorization: tma <init data>" header.
+const Scheme = sign.SchemeTMA
+
+// Identity builds a platform-agnostic identity from verified parameters
+// and the decoded user. The launch source is the start parameter.
+func Identity(p *Params, u *User) *sign.Identity {
+	return &sign.Identity{
+		Platform:  sign.Telegram,
+		UserID:    strconv.Itoa(u.ID),
+		Name:      strings.TrimSpace(u.FirstName + " " + u.LastName),
+		Username:  u.UserName,
+		Language:  u.Language,
+		AvatarURL: u.PhotoURL,
+		Premium:   u.IsPremium,
+		Source:    p.StartParam,
+	}
+}
+
+// Verifier verifies init data with a bot token and implements
+// sign.Verifier. Init data without a user is rejected.
+type Verifier struct {
+	// Token is the bot token
+	Token string
+
+	// Limits bounds the input; nil means sign.DefaultLimits
+	Limits *sign.Limits
+}
+
+// Scheme implements sign.Verifier.
+func (v *Verifier) Scheme() string { return Scheme }
+
+// Verify implements sign.Verifier.
+func (v *Verifier) Verify(raw string) (*sign.Identity, bool) {
+	p, ok := VerifyLimits(raw, v.Token, v.Limits)
+	if !ok || p.UserData == "" {
+		return nil, false
+	}
+	u, err := p.User()
+	if err != nil {
+		return nil, false
+	}
+	return Identity(p, u), true
+}
@@ -0,0 +1,541 @@
+package tma
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxJSONDepth bounds the nesting of skipped values in user and chat objects.
+const maxJSONDepth = 32
+
+// DecodeError reports malformed JSON or a value of the wrong type
+// found while decoding a user or chat object.
+type DecodeError struct {
+	// Field is the init data field being decoded ("user", "receiver"
+	// or "chat"); it is empty for direct DecodeUser and DecodeChat calls.
+	Field string
+
+	// Offset is the byte offset in the input where the error was found
+	Offset int
+
+	// Msg describes the error
+	Msg string
+}
+
+func (e *DecodeError) Error() string {
+	field := e.Field
+	if field == "" {
+		field = "JSON"
+	}
+	return "tma: invalid " + field + " at offset " + strconv.Itoa(e.Offset) + ": " + e.Msg
+}
+
+// DecodeUser decodes the JSON user object in data into u without reflection.
+//
+// Decoding follows encoding/json: unknown fields are skipped, null values
+// leave the field unchanged, keys are matched case-insensitively when no
+// key matches exactly, and invalid UTF-8 is replaced with U+FFFD. Strings
+// without escape sequences point into data; decoding allocates only for
+// strings that contain escapes or invalid UTF-8.
+func DecodeUser(data string, u *User) error {
+	d := decoder{data: data}
+	if !d.consume('{') {
+		return d.fail("expected object")
+	}
+	for first := true; ; {
+		key, ok := d.nextKey(&first)
+		if !ok {
+			break
+		}
+		if d.null() {
+			continue
+		}
+		if d.user(u, key) {
+			continue
+		}
+		if k, ok := foldKey(key, userKeys); ok {
+			d.user(u, k)
+		} else {
+			d.skip(0)
+		}
+	}
+	return d.end()
+}
+
+// userKeys are the keys of the User fields, as matched by decoder.user.
+var userKeys = []string{
+	"id", "first_name", "last_name", "username", "photo_url", "language_code",
+	"chat_type", "chat_instance", "is_premium", "allows_write_to_pm", "added_to_attachment_menu",
+}
+
+// user decodes the value of the User field with the given key.
+// It returns false if key is not one of userKeys.
+func (d *decoder) user(u *User, key string) bool {
+	switch key {
+	case "id":
+		u.ID = int(d.int(strconv.IntSize))
+	case "first_name":
+		u.FirstName = d.string()
+	case "last_name":
+		u.LastName = d.string()
+	case "username":
+		u.UserName = d.string()
+	case "photo_url":
+		u.PhotoURL = d.string()
+	case "language_code":
+		u.Language = d.string()
+	case "chat_type":
+		u.ChatType = d.string()
+	case "chat_instance":
+		u.ChatInstance = d.string()
+	case "is_premium":
+		u.IsPremium = d.bool()
+	case "allows_write_to_pm":
+		u.AllowsWriteToPM = d.bool()
+	case "added_to_attachment_menu":
+		u.AddedToAttachmentMenu = d.bool()
+	default:
+		return false
+	}
+	return true
+}
+
+// DecodeChat decodes the JSON chat object in data into c without reflection.
+// It follows the same rules as DecodeUser.
+func DecodeChat(data string, c *Chat) error {
+	d := decoder{data: data}
+	if !d.consume('{') {
+		return d.fail("expected object")
+	}
+	for first := true; ; {
+		key, ok := d.nextKey(&first)
+		if !ok {
+			break
+		}
+		if d.null() {
+			continue
+		}
+		if d.chat(c, key) {
+			continue
+		}
+		if k, ok := foldKey(key, chatKeys); ok {
+			d.chat(c, k)
+		} else {
+			d.skip(0)
+		}
+	}
+	return d.end()
+}
+
+// chatKeys are the keys of the Chat fields, as matched by decoder.chat.
+var chatKeys = []string{"id", "type", "title", "username", "photo_url"}
+
+// chat decodes the value of the Chat field with the given key.
+// It returns false if key is not one of chatKeys.
+func (d *decoder) chat(c *Chat, key string) bool {
+	switch key {
+	case "id":
+		c.ID = d.int(64)
+	case "type":
+		c.Type = d.string()
+	case "title":
+		c.Title = d.string()
+	case "username":
+		c.UserName = d.string()
+	case "photo_url":
+		c.PhotoURL = d.string()
+	default:
+		return false
+	}
+	return true
+}
+
+// foldKey returns the key in keys equal to key under Unicode case folding,
+// the fallback encoding/json uses when no field name matches exactly.
+func foldKey(key string, keys []string) (string, bool) {
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// decoder is a minimal JSON reader for the fixed user and chat schemas.
+// After the first error every method is a no-op returning zero values.
+type decoder struct {
+	data string
+	pos  int
+	err  *DecodeError
+}
+
+// fail records the first error at the current offset and returns it.
+func (d *decoder) fail(msg string) error {
+	if d.err == nil {
+		d.err = &DecodeError{Offset: d.pos, Msg: msg}
+	}
+	return d.err
+}
+
+func (d *decoder) skipSpace() {
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case ' ', '\t', '\n', '\r':
+			d.pos++
+		default:
+			return
+		}
+	}
+}
+
+// consume skips whitespace and reads c if it is the next byte.
+func (d *decoder) consume(c byte) bool {
+	d.skipSpace()
+	if d.pos < len(d.data) && d.data[d.pos] == c {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+// nextKey reads the next member key of the current object and its ':'.
+// It returns false at the closing brace or on error.
+func (d *decoder) nextKey(first *bool) (string, bool) {
+	if d.err != nil {
+		return "", false
+	}
+	if *first {
+		*first = false
+		if d.consume('}') {
+			return "", false
+		}
+	} else if !d.consume(',') {
+		if !d.consume('}') {
+			d.fail("expected ',' or '}'")
+		}
+		return "", false
+	}
+
+	d.skipSpace()
+	if d.pos >= len(d.data) || d.data[d.pos] != '"' {
+		d.fail("expected object key")
+		return "", false
+	}
+	key := d.string()
+	if !d.consume(':') {
+		d.fail("expected ':'")
+		return "", false
+	}
+	return key, d.err == nil
+}
+
+// end checks that nothing but whitespace follows the top-level value.
+func (d *decoder) end() error {
+	if d.err != nil {
+		return d.err
+	}
+	d.skipSpace()
+	if d.pos != len(d.data) {
+		return d.fail("unexpected data after object")
+	}
+	return nil
+}
+
+// literal reads lit if the input continues with it.
+func (d *decoder) literal(lit string) bool {
+	if len(d.data)-d.pos >= len(lit) && d.data[d.pos:d.pos+len(lit)] == lit {
+		d.pos += len(lit)
+		return true
+	}
+	return false
+}
+
+// null skips whitespace and reads a null literal if present.
+func (d *decoder) null() bool {
+	d.skipSpace()
+	return d.literal("null")
+}
+
+func (d *decoder) bool() bool {
+	d.skipSpace()
+	switch {
+	case d.literal("true"):
+		return true
+	case d.literal("false"):
+		return false
+	}
+	d.fail("expected boolean")
+	return false
+}
+
+// number reads a JSON number and reports whether it is an integer.
+func (d *decoder) number() (string, bool) {
+	start := d.pos
+	integer := true
+
+	if d.pos < len(d.data) && d.data[d.pos] == '-' {
+		d.pos++
+	}
+	switch {
+	case d.pos < len(d.data) && d.data[d.pos] == '0':
+		d.pos++
+	case d.digits() == 0:
+		d.fail("invalid number")
+		return "", false
+	}
+	if d.pos < len(d.data) && d.data[d.pos] == '.' {
+		d.pos++
+		integer = false
+		if d.digits() == 0 {
+			d.fail("invalid number")
+			return "", false
+		}
+	}
+	if d.pos < len(d.data) && (d.data[d.pos] == 'e' || d.data[d.pos] == 'E') {
+		d.pos++
+		integer = false
+		if d.pos < len(d.data) && (d.data[d.pos] == '+' || d.data[d.pos] == '-') {
+			d.pos++
+		}
+		if d.digits() == 0 {
+			d.fail("invalid number")
+			return "", false
+		}
+	}
+	return d.data[start:d.pos], integer
+}
+
+// digits reads a run of decimal digits and returns its length.
+func (d *decoder) digits() int {
+	start := d.pos
+	for d.pos < len(d.data) && d.data[d.pos] >= '0' && d.data[d.pos] <= '9' {
+		d.pos++
+	}
+	return d.pos - start
+}
+
+// int reads an integer that fits in bitSize bits.
+func (d *decoder) int(bitSize int) int64 {
+	d.skipSpace()
+	start := d.pos
+	if d.pos >= len(d.data) || (d.data[d.pos] != '-' && (d.data[d.pos] < '0' || d.data[d.pos] > '9')) {
+		d.fail("expected number")
+		return 0
+	}
+	s, integer := d.number()
+	if d.err != nil {
+		return 0
+	}
+	if !integer {
+		d.pos = start
+		d.fail("expected integer")
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 10, bitSize)
+	if err != nil {
+		d.pos = start
+		d.fail("integer out of range")
+		return 0
+	}
+	return v
+}
+
+// plainByte reports whether a byte stands for itself in a JSON string:
+// ASCII other than control characters, '"' and '\\'.
+var plainByte = func() (t [256]bool) {
+	for c := 0x20; c < utf8.RuneSelf; c++ {
+		t[c] = c != '"' && c != '\\'
+	}
+	return t
+}()
+
+// string reads a JSON string. Strings without escapes or invalid UTF-8
+// are returned as substrings of the input; the rest are decoded into a
+// new string.
+func (d *decoder) string() string {
+	d.skipSpace()
+	if d.pos >= len(d.data) || d.data[d.pos] != '"' {
+		d.fail("expected string")
+		return ""
+	}
+	d.pos++
+
+	start := d.pos
+	for d.pos < len(d.data) {
+		c := d.data[d.pos]
+		if plainByte[c] {
+			d.pos++
+			continue
+		}
+		switch {
+		case c == '"':
+			d.pos++
+			return d.data[start : d.pos-1]
+		case c == '\\':
+			return d.unescape(start)
+		case c < 0x20:
+			d.fail("control character in string")
+			return ""
+		}
+		r, size := utf8.DecodeRuneInString(d.data[d.pos:])
+		if r == utf8.RuneError && size == 1 {
+			return d.unescape(start) // Invalid UTF-8 is replaced
+		}
+		d.pos += size
+	}
+	d.fail("unterminated string")
+	return ""
+}
+
+// unescape decodes the rest of a string starting at start, where the
+// first escape sequence or invalid UTF-8 is at the current offset. Each
+// byte of invalid UTF-8 becomes U+FFFD, as with encoding/json.
+func (d *decoder) unescape(start int) string {
+	buf := make([]byte, 0, len(d.data)-start)
+	buf = append(buf, d.data[start:d.pos]...)
+
+	for d.pos < len(d.data) {
+		c := d.data[d.pos]
+		switch {
+		case c == '"':
+			d.pos++
+			return string(buf)
+		case c < 0x20:
+			d.fail("control character in string")
+			return ""
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRuneInString(d.data[d.pos:])
+			buf = utf8.AppendRune(buf, r)
+			d.pos += size
+			continue
+		case c != '\\':
+			buf = append(buf, c)
+			d.pos++
+			continue
+		}
+
+		if d.pos+1 >= len(d.data) {
+			break
+		}
+		d.pos += 2
+		switch d.data[d.pos-1] {
+		case '"', '\\', '/':
+			buf = append(buf, d.data[d.pos-1])
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			r, ok := d.hex4()
+			if !ok {
+				return ""
+			}
+			if utf16.IsSurrogate(r) {
+				// A valid pair is decoded; lone surrogates become U+FFFD
+				if d.literal(`\u`) {
+					r2, ok := d.hex4()
+					if !ok {
+						return ""
+					}
+					if dec := utf16.DecodeRune(r, r2); dec != utf8.RuneError {
+						r = dec
+					} else {
+						buf = utf8.AppendRune(buf, utf8.RuneError)
+						r = r2
+						if utf16.IsSurrogate(r) {
+							r = utf8.RuneError
+						}
+					}
+				} else {
+					r = utf8.RuneError
+				}
+			}
+			buf = utf8.AppendRune(buf, r)
+		default:
+			d.pos--
+			d.fail("invalid escape sequence")
+			return ""
+		}
+	}
+	d.fail("unterminated string")
+	return ""
+}
+
+// hex4 reads the four hex digits of a \u escape.
+func (d *decoder) hex4() (rune, bool) {
+	if len(d.data)-d.pos < 4 {
+		d.fail("invalid unicode escape")
+		return 0, false
+	}
+	var r rune
+	for i := 0; i < 4; i++ {
+		c := d.data[d.pos+i]
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c -= 'a' - 10
+		case c >= 'A' && c <= 'F':
+			c -= 'A' - 10
+		default:
+			d.fail("invalid unicode escape")
+			return 0, false
+		}
+		r = r<<4 | rune(c)
+	}
+	d.pos += 4
+	return r, true
+}
+
+// skip reads and discards any JSON value.
+func (d *decoder) skip(depth int) {
+	if depth > maxJSONDepth {
+		d.fail("value nested too deeply")
+		return
+	}
+	d.skipSpace()
+	if d.pos >= len(d.data) {
+		d.fail("unexpected end of input")
+		return
+	}
+
+	switch c := d.data[d.pos]; {
+	case c == '"':
+		d.string()
+	case c == '{':
+		d.pos++
+		for first := true; ; {
+			if _, ok := d.nextKey(&first); !ok {
+				return
+			}
+			d.skip(depth + 1)
+		}
+	case c == '[':
+		d.pos++
+		if d.consume(']') {
+			return
+		}
+		for d.err == nil {
+			d.skip(depth + 1)
+			if d.consume(']') {
+				return
+			}
+			if !d.consume(',') {
+				d.fail("expected ',' or ']'")
+			}
+		}
+	case c == '-' || (c >= '0' && c <= '9'):
+		d.number()
+	case d.literal("true"), d.literal("false"), d.literal("null"):
+	default:
+		d.fail("unexpected character")
+	}
+}
@@ -0,0 +1,181 @@
+package tma
+
+import (
+	stdjson "encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestDecodeUser(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty object", `{}`},
+		{"full", `{"id":1093776793,"first_name":"Артур","last_name":"Франк","username":"gmelum","photo_url":"https:\/\/t.me\/i\/userpic\/320\/x.svg","language_code":"ru","chat_type":"private","chat_instance":"-123","is_premium":true,"allows_write_to_pm":false,"added_to_attachment_menu":true}`},
+		{"whitespace", " \n{ \"id\" : 7 ,\t\"first_name\" : \"Ann\" }\r\n"},
+		{"escapes", `{"first_name":"a\"b\\c\/d\b\f\n\r\t","last_name":"\u0416\u00E9"}`},
+		{"surrogate pair", `{"first_name":"\ud83d\ude00 smile","last_name":"😀"}`},
+		{"lone surrogate", `{"first_name":"x\ud83dy","last_name":"\ude00","username":"\ud83dA"}`},
+		{"escaped key", `{"first\u005fname":"Ann"}`},
+		{"unknown fields", `{"is_bot":false,"extra":{"a":[1,2.5e3,-0.1,{"b":null}],"c":"}"},"id":5,"list":[],"obj":{}}`},
+		{"null values", `{"id":null,"first_name":null,"is_premium":null}`},
+		{"duplicate keys", `{"id":1,"id":2}`},
+		{"negative id", `{"id":-42}`},
+		{"case-insensitive keys", `{"ID":1,"First_Name":"Ann","USERNAME":"ann","Photo_URL":"p","Language_Code":"en","Chat_Type":"private","CHAT_INSTANCE":"1","Is_Premium":true,"ALLOWS_WRITE_TO_PM":true,"Added_To_Attachment_Menu":true,"Last_Name":"X"}`},
+		{"folded duplicate keys", `{"ID":1,"id":2,"Id":3}`},
+		{"kelvin sign key", "{\"\u212Aey\":1,\"is_\u017Fremium\":true}"},
+		{"invalid UTF-8", "{\"first_name\":\"a\xffb\",\"last_name\":\"\xc3(\",\"username\":\"\xe2\x82\"}"},
+		{"invalid UTF-8 with escapes", "{\"first_name\":\"\xff\\n\xc3\xa9\",\"last_name\":\"\\t\xed\xa0\x80\"}"},
+		{"invalid UTF-8 key", "{\"first_name\xff\":\"Ann\",\"\xffid\":3}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want User
+			if err := stdjson.Unmarshal([]byte(tt.data), &want); err != nil {
+				t.Fatalf("encoding/json: %v", err)
+			}
+
+			var got User
+			if err := DecodeUser(tt.data, &got); err != nil {
+				t.Fatalf("DecodeUser() error = %v", err)
+			}
+			if got != want {
+				t.Errorf("DecodeUser() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeUser_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ``},
+		{"not an object", `[1]`},
+		{"invalid json", `invalid json`},
+		{"unterminated object", `{"id":1`},
+		{"trailing comma", `{"id":1,}`},
+		{"missing colon", `{"id" 1}`},
+		{"trailing data", `{"id":1} x`},
+		{"float id", `{"id":1.5}`},
+		{"id overflow", `{"id":99999999999999999999}`},
+		{"string id", `{"id":"1"}`},
+		{"number name", `{"first_name":1}`},
+		{"bad bool", `{"is_premium":"true"}`},
+		{"bad escape", `{"first_name":"\x"}`},
+		{"short unicode escape", `{"first_name":"\u12"}`},
+		{"control character", "{\"first_name\":\"a\nb\"}"},
+		{"unterminated string", `{"first_name":"abc`},
+		{"bad number", `{"extra":01}`},
+		{"bad literal", `{"extra":tru}`},
+		{"bad array", `{"extra":[1 2]}`},
+		{"too deep", `{"x":` + deep(maxJSONDepth+2) + `}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u User
+			err := DecodeUser(tt.data, &u)
+			var de *DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("DecodeUser(%q) error = %v, want *DecodeError", tt.data, err)
+			}
+			if stdjson.Unmarshal([]byte(tt.data), &u) == nil && tt.name != "too deep" {
+				t.Errorf("encoding/json accepts %q", tt.data)
+			}
+		})
+	}
+}
+
+func deep(n int) string {
+	s := ""
+	for i := 0; i < n; i++ {
+		s += "["
+	}
+	for i := 0; i < n; i++ {
+		s += "]"
+	}
+	return s
+}
+
+func TestDecodeChat(t *testing.T) {
+	data := `{"id":-1001234567890,"type":"supergroup","title":"Café chat","username":"cafe","photo_url":"https://t.me/i/c.jpg","extra":[true]}`
+
+	var want Chat
+	if err := stdjson.Unmarshal([]byte(data), &want); err != nil {
+		t.Fatal(err)
+	}
+	var got Chat
+	if err := DecodeChat(data, &got); err != nil || got != want {
+		t.Errorf("DecodeChat() = %+v, %v; want %+v", got, err, want)
+	}
+
+	// Keys in another case and invalid UTF-8, as with encoding/json
+	folded := "{\"ID\":-1,\"Type\":\"group\",\"TITLE\":\"Caf\xe9\",\"UserName\":\"c\",\"Photo_Url\":\"u\"}"
+	var wantFolded, gotFolded Chat
+	if err := stdjson.Unmarshal([]byte(folded), &wantFolded); err != nil {
+		t.Fatal(err)
+	}
+	if err := DecodeChat(folded, &gotFolded); err != nil || gotFolded != wantFolded {
+		t.Errorf("DecodeChat(%q) = %+v, %v; want %+v", folded, gotFolded, err, wantFolded)
+	}
+
+	p := Params{ChatData: data, ReceiverData: `{"id":9,"first_name":"Bob"}`}
+	if c, err := p.Chat(); err != nil || *c != want {
+		t.Errorf("Chat() = %+v, %v; want %+v", c, err, want)
+	}
+	if r, err := p.Receiver(); err != nil || r.ID != 9 || r.FirstName != "Bob" {
+		t.Errorf("Receiver() = %+v, %v", r, err)
+	}
+}
+
+func TestDecodeUser_Allocs(t *testing.T) {
+	data := `{"id":123,"first_name":"John","last_name":"Doe","username":"johndoe","language_code":"en","is_premium":true,"extra":{"a":[1]}}`
+	var u User
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = DecodeUser(data, &u)
+	})
+	if allocs != 0 {
+		t.Errorf("DecodeUser() allocs = %v, want 0", allocs)
+	}
+}
+
+func TestParams_UnmarshalUser(t *testing.T) {
+	p := Params{UserData: `{"id":5,"is_bot":true}`}
+	var v struct {
+		ID    int  `json:"id"`
+		IsBot bool `json:"is_bot"`
+	}
+	if err := p.UnmarshalUser(&v); err != nil || v.ID != 5 || !v.IsBot {
+		t.Errorf("UnmarshalUser() = %+v, %v", v, err)
+	}
+}
+
+func BenchmarkDecodeUser(b *testing.B) {
+	data := `{"id":1093776793,"first_name":"Артур","last_name":"Франк","username":"gmelum","language_code":"ru","is_premium":true,"allows_write_to_pm":true}`
+	p := Params{UserData: data}
+
+	b.Run("DecodeUser", func(b *testing.B) {
+		b.ReportAllocs()
+		var u User
+		for i := 0; i < b.N; i++ {
+			_ = DecodeUser(data, &u)
+		}
+	})
+	b.Run("User", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = p.User()
+		}
+	})
+	b.Run("UnmarshalUser", func(b *testing.B) {
+		b.ReportAllocs()
+		var u User
+		for i := 0; i < b.N; i++ {
+			_ = p.UnmarshalUser(&u)
+		}
+	})
+}
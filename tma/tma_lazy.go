@@ -0,0 +1,111 @@
+package tma
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotPresent is returned by User, Receiver and Chat when the init data
+// does not carry the field.
+var ErrNotPresent = errors.New("tma: field not present in init data")
+
+// cached holds the JSON fields of Params decoded on first access.
+// Params returned by Verify share one allocation with their cache.
+type cached struct {
+	user     lazy[User]
+	receiver lazy[User]
+	chat     lazy[Chat]
+}
+
+// lazy decodes a value once and keeps the result for concurrent readers.
+type lazy[T any] struct {
+	once sync.Once
+	v    T
+	err  error
+}
+
+// get returns the cached value, decoding data on the first call.
+func (l *lazy[T]) get(field, data string, decode func(string, *T) error) (*T, error) {
+	l.once.Do(func() {
+		l.err = decodeField(field, data, &l.v, decode)
+	})
+	return &l.v, l.err
+}
+
+// decodeField decodes data into v, reporting ErrNotPresent for an empty field
+// and tagging decode errors with the field name.
+func decodeField[T any](field, data string, v *T, decode func(string, *T) error) error {
+	if data == "" {
+		return ErrNotPresent
+	}
+	err := decode(data, v)
+	if de, ok := err.(*DecodeError); ok {
+		de.Field = field
+	}
+	return err
+}
+
+// newParams moves p to the heap together with an empty decode cache.
+func newParams(p *Params) *Params {
+	r := new(struct {
+		p Params
+		c cached
+	})
+	r.p = *p
+	r.p.cache = &r.c
+	return &r.p
+}
+
+// HasUser reports whether the init data carries a user.
+func (p *Params) HasUser() bool { return p.UserData != "" }
+
+// HasReceiver reports whether the init data carries a chat partner.
+func (p *Params) HasReceiver() bool { return p.ReceiverData != "" }
+
+// HasChat reports whether the init data carries a chat.
+func (p *Params) HasChat() bool { return p.ChatData != "" }
+
+// User decodes UserData and returns the user.
+//
+// For Params returned by Verify the user is decoded once, on first access,
+// and the same *User and error are returned to every later caller; the
+// returned User must not be modified. Params built by hand are decoded on
+// every call. Errors are ErrNotPresent, or a *DecodeError with Field "user".
+//
+// Example usage:
+//
+//	user, err := params.User()
+//	if err != nil {
+//	    // handle error
+//	}
+//	fmt.Printf("User ID: %d", user.ID)
+func (p *Params) User() (*User, error) {
+	if p.cache != nil {
+		return p.cache.user.get("user", p.UserData, DecodeUser)
+	}
+	var user User
+	err := decodeField("user", p.UserData, &user, DecodeUser)
+	return &user, err
+}
+
+// Receiver decodes ReceiverData and returns the chat partner.
+// It is cached like User; decode errors carry Field "receiver".
+func (p *Params) Receiver() (*User, error) {
+	if p.cache != nil {
+		return p.cache.receiver.get("receiver", p.ReceiverData, DecodeUser)
+	}
+	var user User
+	err := decodeField("receiver", p.ReceiverData, &user, DecodeUser)
+	return &user, err
+}
+
+// Chat decodes ChatData and returns the chat.
+// It is cached like User; decode errors carry Field "chat".
+func (p *Params) Chat() (*Chat, error) {
+	if p.cache != nil {
+		return p.cache.chat.get("chat", p.ChatData, DecodeChat)
+	}
+	var chat Chat
+	err := decodeField("chat", p.ChatData, &chat, DecodeChat)
+	return &chat, err
+}
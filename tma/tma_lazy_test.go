@@ -0,0 +1,139 @@
+package tma
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const lazyToken = "1111111111:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+
+// signInitData builds init data from key/value pairs signed with token.
+func signInitData(token string, kv ...string) string {
+	lines := make([]string, 0, len(kv)/2)
+	q := url.Values{}
+	for i := 0; i < len(kv); i += 2 {
+		lines = append(lines, kv[i]+"="+kv[i+1])
+		q.Set(kv[i], kv[i+1])
+	}
+	sort.Strings(lines)
+
+	key := hmac.New(sha256.New, []byte("WebAppData"))
+	key.Write([]byte(token))
+	mac := hmac.New(sha256.New, key.Sum(nil))
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	q.Set("hash", hex.EncodeToString(mac.Sum(nil)))
+	return q.Encode()
+}
+
+func TestParams_Lazy(t *testing.T) {
+	raw := signInitData(lazyToken,
+		"user", `{"id":7,"first_name":"Ann"}`,
+		"receiver", `{"id":8,"first_name":"Bob"}`,
+		"chat", `{"id":-100,"type":"group","title":"Team"}`,
+		"auth_date", "1710181745",
+	)
+	p, ok := Verify(raw, lazyToken)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	if !p.HasUser() || !p.HasReceiver() || !p.HasChat() {
+		t.Fatalf("Has*() = %v, %v, %v; want true", p.HasUser(), p.HasReceiver(), p.HasChat())
+	}
+
+	// Concurrent readers all see the single decoded value
+	var wg sync.WaitGroup
+	users := make([]*User, 8)
+	for i := range users {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			users[i], _ = p.User()
+		}(i)
+	}
+	wg.Wait()
+	for _, u := range users {
+		if u != users[0] || u.ID != 7 || u.FirstName != "Ann" {
+			t.Fatalf("User() = %p %+v, want %p", u, u, users[0])
+		}
+	}
+
+	if r, err := p.Receiver(); err != nil || r.ID != 8 {
+		t.Errorf("Receiver() = %+v, %v", r, err)
+	}
+	if c, err := p.Chat(); err != nil || c.ID != -100 || c.Title != "Team" {
+		t.Errorf("Chat() = %+v, %v", c, err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = p.User()
+		_, _ = p.Chat()
+	})
+	if allocs != 0 {
+		t.Errorf("cached User() and Chat() allocs = %v, want 0", allocs)
+	}
+}
+
+func TestParams_LazyErrors(t *testing.T) {
+	raw := signInitData(lazyToken, "user", `{"id":"7"}`, "auth_date", "1710181745")
+	p, ok := Verify(raw, lazyToken)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	_, err := p.User()
+	var de *DecodeError
+	if !errors.As(err, &de) || de.Field != "user" {
+		t.Fatalf("User() error = %v, want *DecodeError for user", err)
+	}
+	if _, err2 := p.User(); err2 != err {
+		t.Errorf("second User() error = %v, want the cached %v", err2, err)
+	}
+
+	if p.HasChat() {
+		t.Error("HasChat() = true, want false")
+	}
+	if _, err := p.Chat(); !errors.Is(err, ErrNotPresent) {
+		t.Errorf("Chat() error = %v, want ErrNotPresent", err)
+	}
+	if _, err := (&Params{}).User(); !errors.Is(err, ErrNotPresent) {
+		t.Errorf("User() on empty Params error = %v, want ErrNotPresent", err)
+	}
+}
+
+func TestParams_DetachResetsCache(t *testing.T) {
+	raw := []byte(signInitData(lazyToken, "user", `{"id":7,"first_name":"Ann"}`))
+	p, ok := VerifyBytes(raw, lazyToken)
+	if !ok {
+		t.Fatal("VerifyBytes() = false, want true")
+	}
+	if _, err := p.User(); err != nil {
+		t.Fatal(err)
+	}
+
+	p.Detach()
+	for i := range raw {
+		raw[i] = 'x'
+	}
+	if u, err := p.User(); err != nil || u.FirstName != "Ann" {
+		t.Errorf("User() after Detach = %+v, %v", u, err)
+	}
+}
+
+func BenchmarkParams_UserCached(b *testing.B) {
+	p, ok := Verify(signInitData(lazyToken, "user", `{"id":123,"first_name":"John","last_name":"Doe","username":"johndoe"}`), lazyToken)
+	if !ok {
+		b.Fatal("Verify() = false")
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = p.User()
+	}
+}
@@ -4,17 +4,11 @@
 package tma
 
 import (
+	"encoding/json"
 	"strconv"
 	"time"
-
-	jsoniter "github.com/json-iterator/go"
 )
 
-// json is a package-level JSON encoder/decoder instance configured to be
-// compatible with the standard library's JSON implementation while offering
-// better performance. It's used for all JSON operations in this package.
-var json = jsoniter.ConfigCompatibleWithStandardLibrary
-
 // Params represents the initialization parameters received from a Telegram Mini App.
 // These parameters contain information about the user, chat, and authentication.
 //
@@ -22,9 +16,20 @@ var json = jsoniter.ConfigCompatibleWithStandardLibrary
 // ensuring compatibility with different data formats.
 type Params struct {
 	// UserData contains the serialized user information in JSON format.
-	// This field should be parsed using the User() method to access structured data.
+	// This field should be parsed using the User() method to access structured data;
+	// HasUser reports whether it is present.
 	UserData     string    `json:"user" msgpack:"user"`
 
+	// ReceiverData contains the serialized chat partner in JSON format,
+	// sent for apps opened from the attachment menu in a private chat.
+	// This field should be parsed using the Receiver() method.
+	ReceiverData string    `json:"receiver" msgpack:"receiver"`
+
+	// ChatData contains the serialized chat in JSON format, sent for apps
+	// opened from the attachment menu in a group or channel.
+	// This field should be parsed using the Chat() method.
+	ChatData     string    `json:"chat" msgpack:"chat"`
+
 	// ChatInstance is a unique identifier for the chat session where the app was launched.
 	// This helps distinguish between different chat instances.
 	ChatInstance string    `json:"chat_instance" msgpack:"chat_instance"`
@@ -33,6 +38,10 @@ type Params struct {
 	// Common values include "private", "group", "channel", etc.
 	ChatType     string    `json:"chat_type" msgpack:"chat_type"`
 
+	// StartParam is the value of the startattach or startapp parameter
+	// of the link the app was opened with.
+	StartParam   string    `json:"start_param" msgpack:"start_param"`
+
 	// AuthDate represents the timestamp when the authentication occurred.
 	// This is typically parsed from a Unix timestamp string.
 	AuthDate     time.Time `json:"auth_date" msgpack:"auth_date"`
@@ -40,6 +49,9 @@ type Params struct {
 	// Hash is the verification hash used to validate the authenticity
 	// of the received parameters. This should be verified before trusting the data.
 	Hash         string    `json:"hash" msgpack:"hash"`
+
+	// cache holds the lazily decoded user, receiver and chat.
+	cache *cached
 }
 
 // User represents a Telegram user with all available information from the Mini Apps
@@ -85,26 +97,28 @@ type User struct {
 	AddedToAttachmentMenu bool   `json:"added_to_attachment_menu" msgpack:"added_to_attachment_menu"`
 }
 
-// User parses the UserData field and returns a structured User object.
-//
-// Returns:
-//   - *User: A pointer to the parsed User structure
-//   - error: Any error that occurred during JSON unmarshaling
-//
-// Example usage:
-//
-//	user, err := params.User()
-//	if err != nil {
-//	    // handle error
-//	}
-//	fmt.Printf("User ID: %d", user.ID)
-func (p *Params) User() (*User, error) {
-	var user User
-
-	// Unmarshal the JSON-encoded user data into the User struct
-	err := json.Unmarshal([]byte(p.UserData), &user)
-
-	return &user, err
+// Chat represents the chat the Mini App was opened from via the attachment menu.
+type Chat struct {
+	// ID is the unique identifier of the chat.
+	ID       int64  `json:"id" msgpack:"id"`
+
+	// Type is the chat type: "group", "supergroup" or "channel".
+	Type     string `json:"type" msgpack:"type"`
+
+	// Title is the chat title.
+	Title    string `json:"title" msgpack:"title"`
+
+	// UserName is the chat username (optional).
+	UserName string `json:"username" msgpack:"username"`
+
+	// PhotoURL is a link to the chat photo (optional).
+	PhotoURL string `json:"photo_url" msgpack:"photo_url"`
+}
+
+// UnmarshalUser decodes UserData into v with encoding/json, for callers
+// that need fields User does not declare.
+func (p *Params) UnmarshalUser(v any) error {
+	return json.Unmarshal([]byte(p.UserData), v)
 }
 
 // set updates the specified field in the Params struct based on the provided key.
@@ -116,8 +130,11 @@ func (p *Params) User() (*User, error) {
 //
 // Supported keys and value formats:
 //   - "user": Sets the raw UserData string (should be valid JSON)
+//   - "receiver": Sets the raw ReceiverData string (should be valid JSON)
+//   - "chat": Sets the raw ChatData string (should be valid JSON)
 //   - "chat_instance": Sets the ChatInstance string directly
 //   - "chat_type": Sets the ChatType string directly
+//   - "start_param": Sets the StartParam string directly
 //   - "auth_date": Parses the value as a Unix timestamp string (seconds since epoch)
 //
 // Note: This method silently ignores unsupported keys and parsing errors.
@@ -125,10 +142,16 @@ func (p *Params) set(key string, value string) {
 	switch key {
 	case "user":
 		p.UserData = value
+	case "receiver":
+		p.ReceiverData = value
+	case "chat":
+		p.ChatData = value
 	case "chat_instance":
 		p.ChatInstance = value
 	case "chat_type":
 		p.ChatType = value
+	case "start_param":
+		p.StartParam = value
 	case "auth_date":
 		// Attempt to parse the value as a Unix timestamp
 		if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
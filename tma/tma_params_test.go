@@ -36,6 +36,14 @@ func TestParams_set(t *testing.T) {
 				return p.ChatType == "private"
 			},
 		},
+		{
+			name:  "start_param sets StartParam",
+			key:   "start_param",
+			value: "ref_42",
+			expected: func(p *Params) bool {
+				return p.StartParam == "ref_42"
+			},
+		},
 		{
 			name:  "auth_date sets valid time",
 			key:   "auth_date",
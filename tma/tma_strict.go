@@ -0,0 +1,44 @@
+package tma
+
+import (
+	"strconv"
+
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// VerifyStrict validates init data like Verify and additionally rejects:
+//   - malformed and duplicate parameters
+//   - non-numeric and out-of-range auth_date and can_send_after values
+//   - unknown parameters, if opts.RejectUnknownKeys is set
+//
+// Parameter errors are reported as *sign.ParamError before the signature
+// is checked; a signature mismatch is reported as sign.ErrInvalidSignature.
+func VerifyStrict(rawQuery, secret string, opts sign.StrictOptions) (*Params, error) {
+	err := utils.StrictScan(rawQuery, opts.Limits, func(key, val string) error {
+		switch key {
+		case "auth_date", "can_send_after":
+			// Parsed like Params.set, so out-of-range values are rejected
+			// instead of being read as zero
+			if _, err := strconv.ParseInt(val, 10, 64); err != nil || !utils.IsDigits(val, false) {
+				return sign.ErrInvalidNumber
+			}
+		case "query_id", "user", "receiver", "chat", "chat_type", "chat_instance",
+			"start_param", "hash", "signature":
+		default:
+			if opts.RejectUnknownKeys {
+				return sign.ErrUnknownKey
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	params, ok := VerifyLimits(rawQuery, secret, opts.Limits)
+	if !ok {
+		return nil, sign.ErrInvalidSignature
+	}
+	return params, nil
+}
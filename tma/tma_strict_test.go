@@ -0,0 +1,99 @@
+package tma
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/internal/race"
+)
+
+func TestVerifyStrict(t *testing.T) {
+	secret := "1111111111:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	raw := `user=%7B%22id%22%3A1093776793%2C%22first_name%22%3A%22%D0%90%D1%80%D1%82%D1%83%D1%80%22%2C%22last_name%22%3A%22%D0%A4%D1%80%D0%B0%D0%BD%D0%BA%22%2C%22username%22%3A%22gmelum%22%2C%22language_code%22%3A%22ru%22%2C%22is_premium%22%3Atrue%2C%22allows_write_to_pm%22%3Atrue%7D&chat_instance=3411281046910109270&chat_type=private&auth_date=1710181745&hash=ef19060b40a2277fa4debd9c6ad9b37b1e7ac1b6f467e53c66ca6d8df2c3c168`
+
+	tests := []struct {
+		name    string
+		raw     string
+		opts    sign.StrictOptions
+		wantErr error
+	}{
+		{"Valid", raw, sign.StrictOptions{RejectUnknownKeys: true}, nil},
+		{"Unknown key allowed", "foo=1&" + raw, sign.StrictOptions{}, sign.ErrInvalidSignature},
+		{"Unknown key rejected", "foo=1&" + raw, sign.StrictOptions{RejectUnknownKeys: true}, sign.ErrUnknownKey},
+		{"Duplicate key", raw + "&chat_type=group", sign.StrictOptions{}, sign.ErrDuplicateKey},
+		{"Malformed pair", "user&" + raw, sign.StrictOptions{}, sign.ErrMalformedPair},
+		{"Malformed escape", "start_param=%zz&" + raw, sign.StrictOptions{}, sign.ErrMalformedPair},
+		{"Non-numeric auth_date", "auth_date=soon&hash=00", sign.StrictOptions{}, sign.ErrInvalidNumber},
+		{"Overflowing auth_date", "auth_date=99999999999999999999&hash=00", sign.StrictOptions{}, sign.ErrInvalidNumber},
+		{"Bad signature", raw[:len(raw)-1] + "0", sign.StrictOptions{}, sign.ErrInvalidSignature},
+		{"Too many parameters", strings.Repeat("a=1&", 64) + raw, sign.StrictOptions{}, sign.ErrTooLarge},
+		{"Value too long", "start_param=" + strings.Repeat("x", 8<<10+1) + "&" + raw, sign.StrictOptions{}, sign.ErrTooLarge},
+		{"Custom limits", raw, sign.StrictOptions{Limits: &sign.Limits{MaxParams: 4}}, sign.ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := VerifyStrict(tt.raw, secret, tt.opts)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("VerifyStrict() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && p.ChatType != "private" {
+				t.Errorf("ChatType = %q, want %q", p.ChatType, "private")
+			}
+		})
+	}
+}
+
+func TestVerify_Limits(t *testing.T) {
+	if race.Enabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+
+	secret := "1111111111:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	valid := "auth_date=1710181745&hash=" + strings.Repeat("0", 64)
+
+	for name, raw := range map[string]string{
+		"too long":            valid + "&start_param=" + strings.Repeat("x", 16<<10),
+		"too many parameters": strings.Repeat("a=1&", 64) + valid,
+		"key too long":        strings.Repeat("k", 65) + "=1&" + valid,
+	} {
+		if n := testing.AllocsPerRun(10, func() {
+			if _, ok := Verify(raw, secret); ok {
+				t.Errorf("Verify(%s) = true, want false", name)
+			}
+		}); n != 0 {
+			t.Errorf("Verify(%s) allocs = %v, want 0", name, n)
+		}
+	}
+}
+
+func TestVerifyLimits(t *testing.T) {
+	secret := "1111111111:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	raw := `user=%7B%22id%22%3A1093776793%2C%22first_name%22%3A%22%D0%90%D1%80%D1%82%D1%83%D1%80%22%2C%22last_name%22%3A%22%D0%A4%D1%80%D0%B0%D0%BD%D0%BA%22%2C%22username%22%3A%22gmelum%22%2C%22language_code%22%3A%22ru%22%2C%22is_premium%22%3Atrue%2C%22allows_write_to_pm%22%3Atrue%7D&chat_instance=3411281046910109270&chat_type=private&auth_date=1710181745&hash=ef19060b40a2277fa4debd9c6ad9b37b1e7ac1b6f467e53c66ca6d8df2c3c168`
+
+	// raw has five parameters and a user value of 267 escaped bytes
+	for _, tt := range []struct {
+		name   string
+		limits *sign.Limits
+		want   bool
+	}{
+		{"Default", nil, true},
+		{"Within", &sign.Limits{MaxParams: 5, MaxValueLength: 267}, true},
+		{"Too many parameters", &sign.Limits{MaxParams: 4}, false},
+		{"Value too long", &sign.Limits{MaxValueLength: 100}, false},
+	} {
+		if _, ok := VerifyLimits(raw, secret, tt.limits); ok != tt.want {
+			t.Errorf("%s: VerifyLimits() = %v, want %v", tt.name, ok, tt.want)
+		}
+		if _, ok := (&Verifier{Token: secret, Limits: tt.limits}).Verify(raw); ok != tt.want {
+			t.Errorf("%s: Verifier.Verify() = %v, want %v", tt.name, ok, tt.want)
+		}
+		c := NewCache(4, 0, 0)
+		c.Limits = tt.limits
+		if _, ok := c.Verify(raw, secret); ok != tt.want {
+			t.Errorf("%s: Cache.Verify() = %v, want %v", tt.name, ok, tt.want)
+		}
+	}
+}
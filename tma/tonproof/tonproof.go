@@ -0,0 +1,208 @@
+// Package tonproof verifies TON Connect ton_proof responses offline.
+//
+// A Telegram Mini App that connects a TON wallet can request a ton_proof:
+// the wallet signs the app domain, a timestamp and a server-issued payload
+// with its Ed25519 key. The key is taken from the wallet state init sent
+// with the proof, and the state init is bound to the address by its hash,
+// so no blockchain lookup is needed for standard v3, v4 and v5 wallets.
+package tonproof
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	// messagePrefix starts the signed proof message.
+	messagePrefix = "ton-proof-item-v2/"
+
+	// signPrefix is hashed together with the message digest before signing.
+	signPrefix = "\xff\xffton-connect"
+)
+
+// Networks identify the TON network in Request.Network.
+const (
+	Mainnet = "-239"
+	Testnet = "-3"
+)
+
+// now returns the current time; replaced in tests.
+var now = time.Now
+
+// Domain is the app domain a proof was issued for.
+type Domain struct {
+	LengthBytes uint32 `json:"lengthBytes"`
+	Value       string `json:"value"`
+}
+
+// Proof is the ton_proof item returned by the wallet.
+type Proof struct {
+	Timestamp int64  `json:"timestamp"`
+	Domain    Domain `json:"domain"`
+	Signature string `json:"signature"` // standard base64
+	Payload   string `json:"payload"`
+	StateInit string `json:"state_init"` // base64 wallet state init (walletStateInit)
+}
+
+// Request is the proof as sent by the app to its backend, together with
+// the connected account.
+type Request struct {
+	Address   string `json:"address"`    // raw form, "0:<hex>"
+	Network   string `json:"network"`    // Mainnet or Testnet
+	PublicKey string `json:"public_key"` // optional hex public key
+	Proof     Proof  `json:"proof"`
+}
+
+// Config holds the checks applied in addition to the signature.
+type Config struct {
+	// Domains lists the allowed app domains, e.g. "example.com"
+	Domains []string
+
+	// Network is the expected network, Mainnet or Testnet; proofs from
+	// another network are rejected. Empty accepts only Mainnet
+	Network string
+
+	// MaxAge is the largest allowed difference between the proof
+	// timestamp and now; 0 disables the check
+	MaxAge time.Duration
+
+	// CheckPayload validates the payload issued by the server, for example
+	// a stored nonce or a signed deeplink payload; nil skips the check
+	CheckPayload func(payload string) bool
+}
+
+// Wallet is a verified wallet.
+type Wallet struct {
+	Address   Address
+	Version   Version
+	PublicKey ed25519.PublicKey
+	IssuedAt  time.Time
+	Payload   string
+}
+
+// Message builds the ton-proof-item-v2 message signed by the wallet:
+//
+//	"ton-proof-item-v2/" ++ workchain (int32 BE) ++ address hash ++
+//	domain length (uint32 LE) ++ domain ++ timestamp (uint64 LE) ++ payload
+func Message(addr Address, domain string, timestamp int64, payload string) []byte {
+	m := make([]byte, 0, len(messagePrefix)+4+32+4+len(domain)+8+len(payload))
+	m = append(m, messagePrefix...)
+	m = binary.BigEndian.AppendUint32(m, uint32(addr.Workchain))
+	m = append(m, addr.Hash[:]...)
+	m = binary.LittleEndian.AppendUint32(m, uint32(len(domain)))
+	m = append(m, domain...)
+	m = binary.LittleEndian.AppendUint64(m, uint64(timestamp))
+	m = append(m, payload...)
+	return m
+}
+
+// Digest returns the hash the wallet signs for message:
+// sha256(0xffff ++ "ton-connect" ++ sha256(message)).
+func Digest(message []byte) [32]byte {
+	inner := sha256.Sum256(message)
+
+	var b [len(signPrefix) + sha256.Size]byte
+	copy(b[:], signPrefix)
+	copy(b[len(signPrefix):], inner[:])
+	return sha256.Sum256(b[:])
+}
+
+// Verify checks a ton_proof.
+//
+// Parameters:
+//   - req: The proof and account sent by the app
+//   - cfg: Allowed domains, freshness and payload checks
+//
+// Returns:
+//   - *Wallet: The verified wallet if all checks pass
+//   - bool: true if the proof is valid
+//
+// Behavior:
+//  1. Checks the network, domain, timestamp and payload against cfg
+//  2. Parses the state init and requires its hash to equal the address
+//  3. Takes the public key from the state init data and, if req.PublicKey
+//     is set, requires it to match
+//  4. Verifies the Ed25519 signature over the proof message digest
+func Verify(req *Request, cfg *Config) (*Wallet, bool) {
+	if req == nil || cfg == nil {
+		return nil, false
+	}
+	pr := &req.Proof
+
+	network := cfg.Network
+	if network == "" {
+		network = Mainnet
+	}
+	if req.Network != network {
+		return nil, false
+	}
+
+	if int(pr.Domain.LengthBytes) != len(pr.Domain.Value) || !allowed(cfg.Domains, pr.Domain.Value) {
+		return nil, false
+	}
+
+	issued := time.Unix(pr.Timestamp, 0)
+	if cfg.MaxAge > 0 {
+		age := now().Sub(issued)
+		if age > cfg.MaxAge || age < -cfg.MaxAge {
+			return nil, false
+		}
+	}
+
+	if cfg.CheckPayload != nil && !cfg.CheckPayload(pr.Payload) {
+		return nil, false
+	}
+
+	addr, ok := ParseAddress(req.Address)
+	if !ok {
+		return nil, false
+	}
+
+	boc, err := base64.StdEncoding.DecodeString(pr.StateInit)
+	if err != nil {
+		return nil, false
+	}
+	si, ok := ParseStateInit(boc)
+	if !ok || si.Hash != addr.Hash {
+		return nil, false
+	}
+
+	if req.PublicKey != "" {
+		pk, err := hex.DecodeString(req.PublicKey)
+		if err != nil || !si.PublicKey.Equal(ed25519.PublicKey(pk)) {
+			return nil, false
+		}
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(pr.Signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return nil, false
+	}
+
+	digest := Digest(Message(addr, pr.Domain.Value, pr.Timestamp, pr.Payload))
+	if !ed25519.Verify(si.PublicKey, digest[:], sig) {
+		return nil, false
+	}
+
+	return &Wallet{
+		Address:   addr,
+		Version:   si.Version,
+		PublicKey: si.PublicKey,
+		IssuedAt:  issued,
+		Payload:   pr.Payload,
+	}, true
+}
+
+// allowed reports whether domain is in domains.
+func allowed(domains []string, domain string) bool {
+	for _, d := range domains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
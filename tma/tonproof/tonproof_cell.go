@@ -0,0 +1,209 @@
+package tonproof
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash/crc32"
+	"math/bits"
+)
+
+const (
+	// bocMagic starts every serialized bag of cells with the generic layout.
+	bocMagic = 0xb5ee9c72
+
+	// maxCells bounds the number of cells accepted in a state init.
+	maxCells = 1024
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// cell is an ordinary (or library) cell of a deserialized bag of cells.
+type cell struct {
+	d1, d2 byte
+	data   []byte // data bytes as serialized, including the completion tag
+	bits   int
+	refs   [4]int
+	nrefs  int
+	depth  uint16
+	hash   [32]byte
+}
+
+// parseBOC deserializes a single-root bag of cells and computes the
+// representation hash of every cell. Cells are returned in serialization
+// order; the root is at index 0.
+//
+// Only level 0 cells without stored hashes are accepted, which covers
+// every wallet state init. Exotic cells other than library cells are
+// rejected.
+func parseBOC(b []byte) ([]cell, bool) {
+	if len(b) < 6 || binary.BigEndian.Uint32(b) != bocMagic {
+		return nil, false
+	}
+
+	flags := b[4]
+	hasIdx, hasCRC := flags&0x80 != 0, flags&0x40 != 0
+	size, offBytes := int(flags&7), int(b[5])
+	if size < 1 || size > 4 || offBytes < 1 || offBytes > 8 {
+		return nil, false
+	}
+
+	if hasCRC {
+		if len(b) < 10 {
+			return nil, false
+		}
+		body := b[:len(b)-4]
+		if crc32.Checksum(body, castagnoli) != binary.LittleEndian.Uint32(b[len(body):]) {
+			return nil, false
+		}
+		b = body
+	}
+
+	p := 6
+	read := func(n int) (int, bool) {
+		if p+n > len(b) {
+			return 0, false
+		}
+		var v uint64
+		for _, c := range b[p : p+n] {
+			v = v<<8 | uint64(c)
+		}
+		p += n
+		if v > 1<<31 {
+			return 0, false
+		}
+		return int(v), true
+	}
+
+	count, ok1 := read(size)
+	roots, ok2 := read(size)
+	absent, ok3 := read(size)
+	total, ok4 := read(offBytes)
+	root, ok5 := read(size)
+	if !(ok1 && ok2 && ok3 && ok4 && ok5) ||
+		count < 1 || count > maxCells || roots != 1 || absent != 0 || root != 0 {
+		return nil, false
+	}
+
+	if hasIdx {
+		p += count * offBytes
+	}
+	if p+total != len(b) {
+		return nil, false
+	}
+
+	cells := make([]cell, count)
+	for i := range cells {
+		if p+2 > len(b) {
+			return nil, false
+		}
+		c := &cells[i]
+		c.d1, c.d2 = b[p], b[p+1]
+		p += 2
+
+		// Stored hashes (0x10) and non-zero level masks are not supported.
+		if c.d1&0xf0 != 0 {
+			return nil, false
+		}
+		c.nrefs = int(c.d1 & 7)
+		if c.nrefs > 4 {
+			return nil, false
+		}
+
+		// d2 is at most 255 for a full 1023-bit cell, so add in int
+		n := (int(c.d2) + 1) / 2
+		if p+n > len(b) {
+			return nil, false
+		}
+		c.data = b[p : p+n]
+		p += n
+
+		c.bits = n * 8
+		if c.d2&1 != 0 {
+			last := c.data[n-1]
+			if last == 0 {
+				return nil, false
+			}
+			c.bits -= bits.TrailingZeros8(last) + 1
+		}
+
+		if c.d1&8 != 0 && (c.bits != 8+256 || c.data[0] != 2 || c.nrefs != 0) {
+			return nil, false
+		}
+
+		for r := 0; r < c.nrefs; r++ {
+			ref, ok := read(size)
+			if !ok || ref <= i || ref >= count {
+				return nil, false
+			}
+			c.refs[r] = ref
+		}
+	}
+	if p != len(b) {
+		return nil, false
+	}
+
+	// References always point forward, so hashing in reverse order sees
+	// every child before its parent.
+	var buf [2 + 128 + 4*(2+32)]byte
+	for i := count - 1; i >= 0; i-- {
+		c := &cells[i]
+		h := append(buf[:0], c.d1, c.d2)
+		h = append(h, c.data...)
+		for r := 0; r < c.nrefs; r++ {
+			child := &cells[c.refs[r]]
+			if child.depth+1 > c.depth {
+				c.depth = child.depth + 1
+			}
+			h = binary.BigEndian.AppendUint16(h, child.depth)
+		}
+		for r := 0; r < c.nrefs; r++ {
+			h = append(h, cells[c.refs[r]].hash[:]...)
+		}
+		c.hash = sha256.Sum256(h)
+	}
+
+	return cells, true
+}
+
+// bitReader reads bits of a cell from the most significant bit on.
+type bitReader struct {
+	c   *cell
+	pos int
+}
+
+// bit reads a single bit.
+func (r *bitReader) bit() (bool, bool) {
+	if r.pos >= r.c.bits {
+		return false, false
+	}
+	v := r.c.data[r.pos/8]&(0x80>>(r.pos%8)) != 0
+	r.pos++
+	return v, true
+}
+
+// skip advances n bits.
+func (r *bitReader) skip(n int) bool {
+	if r.pos+n > r.c.bits {
+		return false
+	}
+	r.pos += n
+	return true
+}
+
+// bytes reads len(dst) whole bytes starting at any bit offset.
+func (r *bitReader) bytes(dst []byte) bool {
+	if r.pos+len(dst)*8 > r.c.bits {
+		return false
+	}
+	shift := r.pos % 8
+	src := r.c.data[r.pos/8:]
+	for i := range dst {
+		v := src[i] << shift
+		if shift != 0 {
+			v |= src[i+1] >> (8 - shift)
+		}
+		dst[i] = v
+	}
+	r.pos += len(dst) * 8
+	return true
+}
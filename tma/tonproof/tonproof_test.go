@@ -0,0 +1,208 @@
+package tonproof
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+// State inits of wallets for the key derived from seed 01..20, serialized
+// by tonutils-go (v3r2 and v5r1 with CRC32C, v4r2 without).
+const (
+	testV3 = "te6cckEBAwEAoAACATQBAgDe/wAg3SCCAUyXuiGCATOcurGfcbDtRNDTH9MfMdcL/+ME4KTyYIMI1xgg0x/TH9Mf+CMTu/Jj7UTQ0x/TH9P/0VEyuvKhUUS68qIE+QFUEFX5EPKj+ACTINdKltMH1AL7AOjRAaTIyx/LH8v/ye1UAFAAAAAAKamjF3m1Vi6P5lT5QHixEuipi6eQH4U65pW+1+DjkQutBJZkSRV8JA=="
+	testV4 = "te6ccgECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF3m1Vi6P5lT5QHixEuipi6eQH4U65pW+1+DjkQutBJZkQAIBIAQFAgFIBgcE+PKDCNcYINMf0x/THwL4I7vyZO1E0NMf0x/T//QE0VFDuvKhUVG68qIF+QFUEGT5EPKj+AAkpMjLH1JAyx9SMMv/UhD0AMntVPgPAdMHIcAAn2xRkyDXSpbTB9QC+wDoMOAhwAHjACHAAuMAAcADkTDjDQOkyMsfEssfy/8ICQoLAubQAdDTAyFxsJJfBOAi10nBIJJfBOAC0x8hghBwbHVnvSKCEGRzdHK9sJJfBeAD+kAwIPpEAcjKB8v/ydDtRNCBAUDXIfQEMFyBAQj0Cm+hMbOSXwfgBdM/yCWCEHBsdWe6kjgw4w0DghBkc3RyupJfBuMNDA0CASAODwBu0gf6ANTUIvkABcjKBxXL/8nQd3SAGMjLBcsCIs8WUAX6AhTLaxLMzMlz+wDIQBSBAQj0UfKnAgBwgQEI1xj6ANM/yFQgR4EBCPRR8qeCEG5vdGVwdIAYyMsFywJQBs8WUAT6AhTLahLLH8s/yXP7AAIAbIEBCNcY+gDTPzBSJIEBCPRZ8qeCEGRzdHJwdIAYyMsFywJQBc8WUAP6AhPLassfEss/yXP7AAAK9ADJ7VQAeAH6APQEMPgnbyIwUAqhIb7y4FCCEHBsdWeDHrFwgBhQBMsFJs8WWPoCGfQAy2kXyx9SYMs/IMmAQPsABgCKUASBAQj0WTDtRNCBAUDXIMgBzxb0AMntVAFysI4jghBkc3Rygx6xcIAYUAXLBVADzxYj+gITy2rLH8s/yYBA+wCSXwPiAgEgEBEAWb0kK29qJoQICga5D6AhhHDUCAhHpJN9KZEM5pA+n/mDeBKAG3gQFImHFZ8xhAIBWBITABG4yX7UTQ1wsfgAPbKd+1E0IEBQNch9AQwAsjKB8v/ydABgQEI9ApvoTGACASAUFQAZrc52omhAIGuQ64X/wAAZrx32omhAEGuQ64WPwA=="
+	testV5 = "te6cckECFgEAArEAAgE0AQIBFP8A9KQT9LzyyAsDAFGAAAAAP/+uAzzaqxdH8yp8oDxYiXRUxdPID8Kdc0rfa/BxyIXWgksyIAIBIAQFAgFIBgcBAvIIAtzQINdJwSCRW49jINcLHyCCEGV4dG69IYIQc2ludL2wkl8D4IIQZXh0brqOtIAg1yEB0HTXIfpAMPpE+Cj6RDBYvZFb4O1E0IEBQdch9AWDB/QOb6ExkTDhgEDXIXB/2zzgMSDXSYECgLmRMOBw4hIJAgEgCgsBHiDXCx+CEHNpZ2668uCKfwkB5o7w7aLt+yGDCNciAoMI1yMggCDXIdMf0x/TH+1E0NIA0x8g0x/T/9cKAAr5AUDM+RCaKJRfCtsx4fLAh98Cs1AHsPLQhFEluvLghVA2uvLghvgju/LQiCKS+ADeAaR/yMoAyx8BzxbJ7VQgkvgP3nDbPNgSAgEgDA0AGb5fD2omhAgKDrkPoCwCAW4ODwIBSBARABmtznaiaEAg65Drhf/AABmvHfaiaEAQ65DrhY/AABezJftRNBx1yHXCx+AAEbJi+1E0NcKAIAP27aLt+wL0BCFukmwhjkwCIdc5MHCUIccAs44tAdcoIHYeQ2wg10nACPLgkyDXSsAC8uCTINcdBscSwgBSMLDy0InXTNc5MAGk6GwShAe78uCT10rAAPLgk+1V4tIAAcAAkVvg69csCBQgkXCWAdcsCBwS4lIQseMPINdKExQVAJYB+kAB+kT4KPpEMFi68uCR7UTQgQFB1xj0BQSdf8jKAEAEgwf0U/Lgi44UA4MH9Fvy4Iwi1woAIW4Bs7Dy0JDiyFADzxYS9ADJ7VQAcjDXLAgkji0h8uCS0gDtRNDSAFETuvLQj1RQMJExnAGBAUDXIdcKAPLgjuLIygBYzxbJ7VST8sCN4gAQk1vbMeHXTNAgSX3v"
+)
+
+const (
+	testPublicKey = "79b5562e8fe654f94078b112e8a98ba7901f853ae695bed7e0e3910bad049664"
+	testAddrV3    = "0:e8275866cbf174de95888f0ad3373f99dbc883bec5f3df48503550bcea934337"
+	testAddrV4    = "0:e71f2b5f35e5cd52f7dd471e359e5b15a93fc3b88fd6bc5cccacd9d5afb9fc85"
+	testAddrV5    = "0:861ecaab3f815458ace30bc660d99f9be7943705f4edf91452f3a59d0e5d90e8"
+	testDomain    = "app.example.com"
+	testPayload   = "nonce-42"
+	testTimestamp = 1700000000
+)
+
+func testKey() ed25519.PrivateKey {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i + 1)
+	}
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+func testRequest(t *testing.T, address, stateInit string) *Request {
+	addr, ok := ParseAddress(address)
+	if !ok {
+		t.Fatalf("ParseAddress(%q) = false", address)
+	}
+	digest := Digest(Message(addr, testDomain, testTimestamp, testPayload))
+
+	return &Request{
+		Address: address,
+		Network: Mainnet,
+		Proof: Proof{
+			Timestamp: testTimestamp,
+			Domain:    Domain{LengthBytes: uint32(len(testDomain)), Value: testDomain},
+			Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(testKey(), digest[:])),
+			Payload:   testPayload,
+			StateInit: stateInit,
+		},
+	}
+}
+
+func TestParseStateInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		boc     string
+		address string
+		version Version
+	}{
+		{"Wallet v3r2", testV3, testAddrV3, WalletV3},
+		{"Wallet v4r2", testV4, testAddrV4, WalletV4},
+		{"Wallet v5r1", testV5, testAddrV5, WalletV5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boc, _ := base64.StdEncoding.DecodeString(tt.boc)
+			si, ok := ParseStateInit(boc)
+			if !ok {
+				t.Fatal("ParseStateInit() = false, want true")
+			}
+			if got := (Address{Hash: si.Hash}).String(); got != tt.address {
+				t.Errorf("hash = %s, want %s", got, tt.address)
+			}
+			if si.Version != tt.version || hex.EncodeToString(si.PublicKey) != testPublicKey {
+				t.Errorf("got %s %x", si.Version, si.PublicKey)
+			}
+		})
+	}
+
+	boc, _ := base64.StdEncoding.DecodeString(testV3)
+	for name, b := range map[string][]byte{
+		"empty":     nil,
+		"truncated": boc[:len(boc)-10],
+		"bad crc":   append(append([]byte(nil), boc[:len(boc)-1]...), boc[len(boc)-1]^1),
+		"bad magic": append([]byte{0}, boc[1:]...),
+	} {
+		if _, ok := ParseStateInit(b); ok {
+			t.Errorf("ParseStateInit(%s) = true, want false", name)
+		}
+	}
+}
+
+// testBOC serializes cells (d1, d2, data and one-byte refs each) into a
+// bag of cells without index or CRC, rooted at the first cell.
+func testBOC(cells ...[]byte) []byte {
+	var body []byte
+	for _, c := range cells {
+		body = append(body, c...)
+	}
+	b := []byte{0xb5, 0xee, 0x9c, 0x72, 0x01, 0x02, byte(len(cells)), 1, 0, byte(len(body) >> 8), byte(len(body)), 0}
+	return append(b, body...)
+}
+
+func TestParseBOC_FullCell(t *testing.T) {
+	// A 1023-bit cell: d2 = 255, 128 data bytes ending in the completion tag
+	data := make([]byte, 128)
+	data[127] = 0x01
+	b := testBOC(append([]byte{0, 255}, data...))
+
+	cells, ok := parseBOC(b)
+	if !ok || cells[0].bits != 1023 {
+		t.Fatalf("parseBOC() = %v, %v; want a 1023-bit cell", ok, cells)
+	}
+	if _, ok := ParseStateInit(b); ok {
+		t.Error("ParseStateInit(1023-bit cell) = true, want false")
+	}
+}
+
+func TestParseStateInit_UnknownCode(t *testing.T) {
+	// code and data present: bits 00110, completion tag, refs 1 and 2
+	root := []byte{2, 1, 0x34, 1, 2}
+	code := []byte{0, 2, 0xff}
+	data := append([]byte{0, 80}, make([]byte, 40)...) // v3 layout, 320 bits
+
+	if _, ok := ParseStateInit(testBOC(root, code, data)); ok {
+		t.Error("ParseStateInit(unknown code) = true, want false")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now = func() time.Time { return time.Unix(testTimestamp+60, 0) }
+	defer func() { now = time.Now }()
+
+	cfg := &Config{
+		Domains:      []string{"other.example.com", testDomain},
+		MaxAge:       5 * time.Minute,
+		CheckPayload: func(p string) bool { return p == testPayload },
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(r *Request)
+		cfg       *Config
+		wantValid bool
+	}{
+		{"Valid", func(r *Request) {}, cfg, true},
+		{"Valid with public key", func(r *Request) { r.PublicKey = testPublicKey }, cfg, true},
+		{"Valid v4", func(r *Request) { *r = *testRequest(t, testAddrV4, testV4) }, cfg, true},
+		{"Valid v5", func(r *Request) { *r = *testRequest(t, testAddrV5, testV5) }, cfg, true},
+		{"Nil config", func(r *Request) {}, nil, false},
+		{"Valid testnet", func(r *Request) { r.Network = Testnet }, &Config{Domains: cfg.Domains, Network: Testnet}, true},
+		{"Testnet proof for mainnet", func(r *Request) { r.Network = Testnet }, cfg, false},
+		{"Mainnet proof for testnet", func(r *Request) {}, &Config{Domains: cfg.Domains, Network: Testnet}, false},
+		{"Missing network", func(r *Request) { r.Network = "" }, cfg, false},
+		{"Wrong domain", func(r *Request) {}, &Config{Domains: []string{"evil.com"}}, false},
+		{"Domain length mismatch", func(r *Request) { r.Proof.Domain.LengthBytes++ }, cfg, false},
+		{"Expired", func(r *Request) {}, &Config{Domains: cfg.Domains, MaxAge: time.Second}, false},
+		{"Rejected payload", func(r *Request) { r.Proof.Payload = "replayed" }, cfg, false},
+		{"Malformed address", func(r *Request) { r.Address = "EQDoJ1hmy" }, cfg, false},
+		{"Address of another wallet", func(r *Request) { r.Address = testAddrV4 }, cfg, false},
+		{"State init of another wallet", func(r *Request) { r.Proof.StateInit = testV4 }, cfg, false},
+		{"Public key mismatch", func(r *Request) { r.PublicKey = testPublicKey[:62] + "00" }, cfg, false},
+		{"Tampered timestamp", func(r *Request) { r.Proof.Timestamp++ }, cfg, false},
+		{"Bad signature encoding", func(r *Request) { r.Proof.Signature = "!" }, cfg, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testRequest(t, testAddrV3, testV3)
+			tt.modify(req)
+
+			w, ok := Verify(req, tt.cfg)
+			if ok != tt.wantValid {
+				t.Errorf("Verify() = %v, want %v", ok, tt.wantValid)
+			}
+			if ok && (w.Address.String() != req.Address || w.Payload != testPayload) {
+				t.Errorf("unexpected wallet %+v", w)
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	addr, _ := ParseAddress(testAddrV4)
+	digest := Digest(Message(addr, testDomain, testTimestamp, testPayload))
+	req := &Request{
+		Address: testAddrV4,
+		Network: Mainnet,
+		Proof: Proof{
+			Timestamp: testTimestamp,
+			Domain:    Domain{LengthBytes: uint32(len(testDomain)), Value: testDomain},
+			Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(testKey(), digest[:])),
+			Payload:   testPayload,
+			StateInit: testV4,
+		},
+	}
+	cfg := &Config{Domains: []string{testDomain}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Verify(req, cfg)
+	}
+}
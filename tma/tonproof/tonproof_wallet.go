@@ -0,0 +1,165 @@
+package tonproof
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Version identifies a standard wallet contract.
+type Version string
+
+const (
+	WalletV3 Version = "v3"
+	WalletV4 Version = "v4"
+	WalletV5 Version = "v5"
+)
+
+// walletCodes maps the representation hashes of the standard wallet
+// contract codes to their versions.
+var walletCodes = map[[32]byte]Version{
+	codeHash("b61041a58a7980b946e8fb9e198e3c904d24799ffa36574ea4251c41a566f581"): WalletV3, // v3r1
+	codeHash("84dafa449f98a6987789ba232358072bc0f76dc4524002a5d0918b9a75d2d599"): WalletV3, // v3r2
+	codeHash("64dd54805522c5be8a9db59cea0105ccf0d08786ca79beb8cb79e880a8d7322d"): WalletV4, // v4r1
+	codeHash("feb5ff6820e2ff0d9483e7e0d62c817d846789fb4ae580c878866d959dabd5c0"): WalletV4, // v4r2
+	codeHash("20834b7b72b112147e1b2fb457b84e74d1a30f04f737d4f62a668e9552d2b72f"): WalletV5, // v5r1
+}
+
+func codeHash(s string) [32]byte {
+	var h [32]byte
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// Address is a raw TON account address.
+type Address struct {
+	// Workchain is the workchain ID, 0 for the basechain and -1 for the masterchain
+	Workchain int32
+
+	// Hash is the account ID, the hash of the account's initial state
+	Hash [32]byte
+}
+
+// String returns the address in raw form, "<workchain>:<hex hash>".
+func (a Address) String() string {
+	return strconv.FormatInt(int64(a.Workchain), 10) + ":" + hex.EncodeToString(a.Hash[:])
+}
+
+// ParseAddress parses an address in raw form, "<workchain>:<hex hash>",
+// as TON Connect returns it.
+func ParseAddress(s string) (Address, bool) {
+	var a Address
+
+	wc, h, ok := strings.Cut(s, ":")
+	if !ok || len(h) != 64 {
+		return a, false
+	}
+	n, err := strconv.ParseInt(wc, 10, 32)
+	if err != nil {
+		return a, false
+	}
+	if _, err := hex.Decode(a.Hash[:], []byte(h)); err != nil {
+		return a, false
+	}
+	a.Workchain = int32(n)
+	return a, true
+}
+
+// StateInit is the part of a wallet state init needed to check a proof.
+type StateInit struct {
+	// Hash is the representation hash of the state init, which equals the
+	// account ID of the wallet address
+	Hash [32]byte
+
+	// Version is the wallet contract version
+	Version Version
+
+	// PublicKey is the wallet public key stored in the contract data
+	PublicKey ed25519.PublicKey
+}
+
+// ParseStateInit deserializes a wallet state init (a bag of cells) and
+// extracts the public key from its data cell.
+//
+// The wallet version is recognized by the hash of the code cell, which
+// must be one of the standard v3r1, v3r2, v4r1, v4r2 or v5r1 contracts.
+// The data cell must then have the layout of that version:
+//   - v3: seqno:32 subwallet:32 pubkey:256 (320 bits)
+//   - v4: seqno:32 subwallet:32 pubkey:256 plugins:1 (321 bits)
+//   - v5: signature_allowed:1 seqno:32 wallet_id:32 pubkey:256 extensions:1 (322 bits)
+//
+// Returns:
+//   - *StateInit: The state init hash, wallet version and public key
+//   - bool: false if the data is malformed or not a standard wallet
+func ParseStateInit(boc []byte) (*StateInit, bool) {
+	cells, ok := parseBOC(boc)
+	if !ok {
+		return nil, false
+	}
+
+	// StateInit: split_depth:(Maybe (## 5)) special:(Maybe TickTock)
+	// code:(Maybe ^Cell) data:(Maybe ^Cell) library:(Maybe ^Cell)
+	root := &cells[0]
+	r := bitReader{c: root}
+	ref, code, data := 0, -1, -1
+	for field := 0; field < 5; field++ {
+		present, ok := r.bit()
+		if !ok {
+			return nil, false
+		}
+		if !present {
+			continue
+		}
+		switch field {
+		case 0:
+			ok = r.skip(5)
+		case 1:
+			ok = r.skip(2)
+		default:
+			if ref >= root.nrefs {
+				return nil, false
+			}
+			switch field {
+			case 2:
+				code = root.refs[ref]
+			case 3:
+				data = root.refs[ref]
+			}
+			ref++
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+	if r.pos != root.bits || ref != root.nrefs || code < 0 || data < 0 {
+		return nil, false
+	}
+
+	version, ok := walletCodes[cells[code].hash]
+	if !ok {
+		return nil, false // Not a standard wallet contract
+	}
+
+	dc := &cells[data]
+	if dc.d1&8 != 0 {
+		return nil, false
+	}
+
+	si := &StateInit{Hash: root.hash, Version: version, PublicKey: make(ed25519.PublicKey, ed25519.PublicKeySize)}
+	r = bitReader{c: dc}
+	switch {
+	case version == WalletV3 && dc.bits == 320, version == WalletV4 && dc.bits == 321:
+		r.pos = 64
+	case version == WalletV5 && dc.bits == 322:
+		r.pos = 65
+	default:
+		return nil, false
+	}
+	if !r.bytes(si.PublicKey) {
+		return nil, false
+	}
+	return si, true
+}
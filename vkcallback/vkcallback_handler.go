@@ -0,0 +1,133 @@
+package vkcallback
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxBodySize limits request bodies read by the Handler.
+const DefaultMaxBodySize = 1 << 20
+
+// Handler is an http.Handler for the Callback API endpoint of one or
+// more communities.
+//
+// It verifies group_id and secret, answers confirmation with the configured
+// string, decodes known event types and dispatches them to the matching
+// callback, falling back to OnEvent. Successfully handled events are
+// answered with "ok"; a callback error is answered with 500, so VK
+// delivers the event again.
+type Handler struct {
+	// Groups maps community IDs (decimal strings) to their configuration.
+	Groups map[string]Group
+
+	// MaxBodySize limits the request body; zero means DefaultMaxBodySize.
+	MaxBodySize int64
+
+	OnMessageNew        func(ctx context.Context, e *Event, o *MessageNewObject) error
+	OnGroupJoin         func(ctx context.Context, e *Event, o *GroupJoinObject) error
+	OnGroupLeave        func(ctx context.Context, e *Event, o *GroupLeaveObject) error
+	OnGroupOfficersEdit func(ctx context.Context, e *Event, o *GroupOfficersEditObject) error
+	OnVKPayTransaction  func(ctx context.Context, e *Event, o *VKPayTransactionObject) error
+
+	// OnDonutSubscription handles all donut_subscription_* events;
+	// e.Type tells them apart.
+	OnDonutSubscription func(ctx context.Context, e *Event, o *DonutSubscriptionObject) error
+
+	// OnEvent handles events no other callback handled.
+	OnEvent func(ctx context.Context, e *Event) error
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	maxBodySize := h.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil || int64(len(body)) > maxBodySize {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	e, group, ok := Verify(body, h.Groups)
+	if !ok {
+		http.Error(w, "invalid secret", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+
+	if e.Type == Confirmation {
+		_, _ = io.WriteString(w, group.Confirmation)
+		return
+	}
+
+	if err := h.dispatch(r.Context(), e); err != nil {
+		http.Error(w, "event not processed", http.StatusInternalServerError)
+		return
+	}
+	_, _ = io.WriteString(w, "ok")
+}
+
+// dispatch decodes the event object and passes it to its callback.
+func (h *Handler) dispatch(ctx context.Context, e *Event) error {
+	switch e.Type {
+	case MessageNew:
+		if h.OnMessageNew != nil {
+			var o MessageNewObject
+			if err := json.Unmarshal(e.Object, &o); err != nil {
+				return err
+			}
+			return h.OnMessageNew(ctx, e, &o)
+		}
+	case GroupJoin:
+		if h.OnGroupJoin != nil {
+			var o GroupJoinObject
+			if err := json.Unmarshal(e.Object, &o); err != nil {
+				return err
+			}
+			return h.OnGroupJoin(ctx, e, &o)
+		}
+	case GroupLeave:
+		if h.OnGroupLeave != nil {
+			var o GroupLeaveObject
+			if err := json.Unmarshal(e.Object, &o); err != nil {
+				return err
+			}
+			return h.OnGroupLeave(ctx, e, &o)
+		}
+	case GroupOfficersEdit:
+		if h.OnGroupOfficersEdit != nil {
+			var o GroupOfficersEditObject
+			if err := json.Unmarshal(e.Object, &o); err != nil {
+				return err
+			}
+			return h.OnGroupOfficersEdit(ctx, e, &o)
+		}
+	case VKPayTransaction:
+		if h.OnVKPayTransaction != nil {
+			var o VKPayTransactionObject
+			if err := json.Unmarshal(e.Object, &o); err != nil {
+				return err
+			}
+			return h.OnVKPayTransaction(ctx, e, &o)
+		}
+	case DonutSubscriptionCreate, DonutSubscriptionProlonged, DonutSubscriptionExpired,
+		DonutSubscriptionCancelled, DonutSubscriptionPriceChanged:
+		if h.OnDonutSubscription != nil {
+			var o DonutSubscriptionObject
+			if err := json.Unmarshal(e.Object, &o); err != nil {
+				return err
+			}
+			return h.OnDonutSubscription(ctx, e, &o)
+		}
+	}
+
+	if h.OnEvent != nil {
+		return h.OnEvent(ctx, e)
+	}
+	return nil
+}
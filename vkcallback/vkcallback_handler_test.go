@@ -0,0 +1,167 @@
+package vkcallback
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elum-utils/sign/vkma"
+)
+
+var testGroups = map[string]Group{
+	"123456": {Secret: "group-secret", Confirmation: "a1b2c3d4"},
+	"777":    {Secret: "other-secret", Confirmation: "zzz"},
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		groups map[string]Group
+		wantOK bool
+	}{
+		{"No groups", `{"type":"message_new","group_id":123456,"secret":"group-secret"}`, nil, false},
+		{"Invalid JSON", `{"type":`, testGroups, false},
+		{"Missing type", `{"group_id":123456,"secret":"group-secret"}`, testGroups, false},
+		{"Unknown group", `{"type":"message_new","group_id":1,"secret":"group-secret"}`, testGroups, false},
+		{"Wrong secret", `{"type":"message_new","group_id":123456,"secret":"other-secret"}`, testGroups, false},
+		{"Missing secret", `{"type":"message_new","group_id":123456}`, testGroups, false},
+		{"Wrong confirmation secret", `{"type":"confirmation","group_id":123456,"secret":"nope"}`, testGroups, false},
+		{"Confirmation without secret", `{"type":"confirmation","group_id":123456}`, testGroups, false},
+		{"Confirmation", `{"type":"confirmation","group_id":123456,"secret":"group-secret"}`, testGroups, true},
+		{"Confirmation for group without secret", `{"type":"confirmation","group_id":1}`, map[string]Group{"1": {Confirmation: "x"}}, false},
+		{"Event for group without secret", `{"type":"message_new","group_id":1}`, map[string]Group{"1": {Confirmation: "x"}}, true},
+		{"Valid", `{"type":"message_new","group_id":777,"secret":"other-secret"}`, testGroups, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, g, ok := Verify([]byte(tt.body), tt.groups)
+			if ok != tt.wantOK {
+				t.Fatalf("Verify() = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && (e == nil || g == nil) {
+				t.Error("Expected non-nil event and group on success")
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var got string
+	h := &Handler{
+		Groups: testGroups,
+		OnMessageNew: func(ctx context.Context, e *Event, o *MessageNewObject) error {
+			got = "message:" + o.Message.Text
+			return nil
+		},
+		OnGroupJoin: func(ctx context.Context, e *Event, o *GroupJoinObject) error {
+			got = "join:" + o.JoinType
+			return nil
+		},
+		OnGroupOfficersEdit: func(ctx context.Context, e *Event, o *GroupOfficersEditObject) error {
+			got = "officers:" + string(o.RoleOld()) + "->" + string(o.RoleNew())
+			return nil
+		},
+		OnVKPayTransaction: func(ctx context.Context, e *Event, o *VKPayTransactionObject) error {
+			return errors.New("ledger unavailable")
+		},
+		OnDonutSubscription: func(ctx context.Context, e *Event, o *DonutSubscriptionObject) error {
+			got = "donut:" + string(e.Type)
+			return nil
+		},
+		OnEvent: func(ctx context.Context, e *Event) error {
+			got = "other:" + string(e.Type)
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantBody string
+		wantCall string
+	}{
+		{
+			name:     "bad secret",
+			body:     `{"type":"message_new","group_id":123456,"secret":"x"}`,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "confirmation",
+			body:     `{"type":"confirmation","group_id":123456,"secret":"group-secret"}`,
+			wantCode: http.StatusOK,
+			wantBody: "a1b2c3d4",
+		},
+		{
+			name:     "message_new",
+			body:     `{"type":"message_new","group_id":123456,"event_id":"e1","v":"5.199","secret":"group-secret","object":{"message":{"id":1,"from_id":42,"peer_id":42,"text":"hello"},"client_info":{"keyboard":true}}}`,
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+			wantCall: "message:hello",
+		},
+		{
+			name:     "group_join",
+			body:     `{"type":"group_join","group_id":123456,"secret":"group-secret","object":{"user_id":42,"join_type":"join"}}`,
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+			wantCall: "join:join",
+		},
+		{
+			name:     "group_officers_edit",
+			body:     `{"type":"group_officers_edit","group_id":123456,"secret":"group-secret","object":{"admin_id":1,"user_id":42,"level_old":1,"level_new":3}}`,
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+			wantCall: "officers:moder->admin",
+		},
+		{
+			name:     "donut",
+			body:     `{"type":"donut_subscription_price_changed","group_id":777,"secret":"other-secret","object":{"user_id":42,"amount_old":100,"amount_new":200,"amount_diff":100}}`,
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+			wantCall: "donut:donut_subscription_price_changed",
+		},
+		{
+			name:     "callback error",
+			body:     `{"type":"vkpay_transaction","group_id":123456,"secret":"group-secret","object":{"from_id":42,"amount":1000}}`,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "fallback",
+			body:     `{"type":"wall_post_new","group_id":123456,"secret":"group-secret","object":{}}`,
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+			wantCall: "other:wall_post_new",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/vk", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if got != tt.wantCall {
+				t.Errorf("callback = %q, want %q", got, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestLevelRole(t *testing.T) {
+	want := []vkma.Role{vkma.RoleNone, vkma.RoleModer, vkma.RoleEditor, vkma.RoleAdmin, vkma.RoleNone}
+	for level, role := range want {
+		if got := levelRole(level); got != role {
+			t.Errorf("levelRole(%d) = %q, want %q", level, got, role)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Package vkcallback provides types for VK Callback API events sent by
+// communities to the application server.
+package vkcallback
+
+import (
+	"encoding/json"
+
+	"github.com/elum-utils/sign/vkma"
+)
+
+// EventType is the type of a Callback API event.
+type EventType string
+
+// Event types decoded by this package. Other types are delivered as-is.
+const (
+	Confirmation                  EventType = "confirmation"
+	MessageNew                    EventType = "message_new"
+	GroupJoin                     EventType = "group_join"
+	GroupLeave                    EventType = "group_leave"
+	GroupOfficersEdit             EventType = "group_officers_edit"
+	VKPayTransaction              EventType = "vkpay_transaction"
+	DonutSubscriptionCreate       EventType = "donut_subscription_create"
+	DonutSubscriptionProlonged    EventType = "donut_subscription_prolonged"
+	DonutSubscriptionExpired      EventType = "donut_subscription_expired"
+	DonutSubscriptionCancelled    EventType = "donut_subscription_cancelled"
+	DonutSubscriptionPriceChanged EventType = "donut_subscription_price_changed"
+)
+
+// Event is a Callback API event envelope.
+//
+// VK Callback API documentation: https://dev.vk.com/api/callback/getting-started
+type Event struct {
+	// Type is the event type
+	Type EventType `json:"type"`
+
+	// GroupID is the community the event belongs to
+	GroupID int `json:"group_id"`
+
+	// EventID uniquely identifies the event; retries carry the same ID
+	EventID string `json:"event_id"`
+
+	// Version is the API version of the object
+	Version string `json:"v"`
+
+	// Object is the raw event object; use the typed decoders to access it
+	Object json.RawMessage `json:"object"`
+
+	// Secret is the secret key configured for the community server
+	Secret string `json:"secret"`
+}
+
+// Message is a private message sent to the community.
+type Message struct {
+	ID                    int    `json:"id"`
+	Date                  int64  `json:"date"`
+	PeerID                int    `json:"peer_id"`
+	FromID                int    `json:"from_id"`
+	Text                  string `json:"text"`
+	Payload               string `json:"payload"`
+	Ref                   string `json:"ref"`
+	RefSource             string `json:"ref_source"`
+	ConversationMessageID int    `json:"conversation_message_id"`
+}
+
+// ClientInfo describes the features supported by the sender's client.
+type ClientInfo struct {
+	ButtonActions  []string `json:"button_actions"`
+	Keyboard       bool     `json:"keyboard"`
+	InlineKeyboard bool     `json:"inline_keyboard"`
+	Carousel       bool     `json:"carousel"`
+	LangID         int      `json:"lang_id"`
+}
+
+// MessageNewObject is the object of a message_new event.
+type MessageNewObject struct {
+	Message    Message    `json:"message"`
+	ClientInfo ClientInfo `json:"client_info"`
+}
+
+// GroupJoinObject is the object of a group_join event.
+type GroupJoinObject struct {
+	UserID   int    `json:"user_id"`
+	JoinType string `json:"join_type"` // "join", "unsure", "accepted", "approved", "request"
+}
+
+// GroupLeaveObject is the object of a group_leave event.
+type GroupLeaveObject struct {
+	UserID int  `json:"user_id"`
+	Self   bool `json:"self"` // false if the user was removed by an administrator
+}
+
+// GroupOfficersEditObject is the object of a group_officers_edit event.
+// Levels use the Callback API numbering; see RoleOld and RoleNew.
+type GroupOfficersEditObject struct {
+	AdminID  int `json:"admin_id"`
+	UserID   int `json:"user_id"`
+	LevelOld int `json:"level_old"`
+	LevelNew int `json:"level_new"`
+}
+
+// RoleOld returns the previous role of the user as a vkma.Role.
+func (o *GroupOfficersEditObject) RoleOld() vkma.Role { return levelRole(o.LevelOld) }
+
+// RoleNew returns the new role of the user as a vkma.Role.
+func (o *GroupOfficersEditObject) RoleNew() vkma.Role { return levelRole(o.LevelNew) }
+
+// levelRole converts a Callback API officer level into the role values
+// used by vk_viewer_group_role in launch parameters.
+func levelRole(level int) vkma.Role {
+	switch level {
+	case 1:
+		return vkma.RoleModer
+	case 2:
+		return vkma.RoleEditor
+	case 3:
+		return vkma.RoleAdmin
+	default:
+		return vkma.RoleNone
+	}
+}
+
+// VKPayTransactionObject is the object of a vkpay_transaction event.
+type VKPayTransactionObject struct {
+	FromID      int    `json:"from_id"`
+	Amount      int    `json:"amount"` // In thousandths of a ruble
+	Description string `json:"description"`
+	Date        int64  `json:"date"`
+}
+
+// DonutSubscriptionObject is the object of donut_subscription_* events.
+// Fields not sent for a particular event type are left zero.
+type DonutSubscriptionObject struct {
+	UserID int `json:"user_id"`
+
+	// create and prolonged
+	Amount           int     `json:"amount"`
+	AmountWithoutFee float64 `json:"amount_without_fee"`
+
+	// price_changed
+	AmountOld            int     `json:"amount_old"`
+	AmountNew            int     `json:"amount_new"`
+	AmountDiff           float64 `json:"amount_diff"`
+	AmountDiffWithoutFee float64 `json:"amount_diff_without_fee"`
+}
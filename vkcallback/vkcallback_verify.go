@@ -0,0 +1,61 @@
+// Package vkcallback provides functionality for verifying VK Callback API
+// events by their group_id and secret, and answering them.
+package vkcallback
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"strconv"
+)
+
+// Group is the Callback API configuration of a single community.
+type Group struct {
+	// Secret is the secret key set in the community's server settings.
+	// An empty Secret accepts only events without a secret, which is
+	// not recommended, and never the confirmation event.
+	Secret string
+
+	// Confirmation is the string VK expects in reply to the
+	// confirmation event.
+	Confirmation string
+}
+
+// Verify decodes a Callback API event and checks it against the
+// configured communities.
+//
+// Parameters:
+//   - body: The raw request body
+//   - groups: A map of community IDs (as decimal strings, like the app IDs
+//     of vkma.Verify) to their configuration
+//
+// Returns:
+//   - *Event: The decoded event if verification succeeds
+//   - *Group: The configuration of the event's community
+//   - bool: true if the community is known and the secret matches
+//
+// Secrets are compared in constant time. Confirmation events are only
+// accepted for communities with a non-empty Secret, since the reply
+// hands out the confirmation string.
+func Verify(body []byte, groups map[string]Group) (*Event, *Group, bool) {
+	if len(groups) == 0 {
+		return nil, nil, false
+	}
+
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil || e.Type == "" {
+		return nil, nil, false
+	}
+
+	group, ok := groups[strconv.Itoa(e.GroupID)]
+	if !ok {
+		return nil, nil, false // Unknown community
+	}
+
+	if e.Type == Confirmation && group.Secret == "" {
+		return nil, nil, false // Never answer unauthenticated confirmations
+	}
+	if subtle.ConstantTimeCompare([]byte(e.Secret), []byte(group.Secret)) != 1 {
+		return nil, nil, false
+	}
+	return &e, &group, true
+}
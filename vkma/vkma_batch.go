@@ -0,0 +1,57 @@
+package vkma
+
+import (
+	"context"
+
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// BatchResult is the outcome of verifying one launch string of a batch.
+type BatchResult struct {
+	// Params holds the parsed parameters if the signature is valid
+	Params *Params
+
+	// Err is sign.ErrInvalidSignature for an invalid item, or the
+	// context error for an item skipped after cancellation
+	Err error
+}
+
+// VerifyBatch verifies every launch string in raws on at most workers
+// goroutines (GOMAXPROCS if workers <= 0) and returns the results in the
+// order of raws.
+//
+// Workers reuse the package's pooled buffers and the pre-keyed HMAC pool
+// of each secret, so a batch allocates little beyond the results. Once ctx
+// is done, items not yet verified get ctx.Err().
+func VerifyBatch(ctx context.Context, raws []string, secrets map[string]string, workers int) []BatchResult {
+	results := make([]BatchResult, len(raws))
+	utils.RunBatch(ctx, len(raws), workers, func(i int, err error) {
+		if err != nil {
+			results[i].Err = err
+			return
+		}
+		results[i] = verifyItem(raws[i], secrets)
+	})
+	return results
+}
+
+// VerifyStream verifies launch strings read from in until it is closed,
+// like VerifyBatch, and sends the results in input order to the returned
+// channel, which is closed when done. At most workers items are in flight.
+//
+// Once ctx is done no further input is read and the channel is closed;
+// results not yet received are dropped.
+func VerifyStream(ctx context.Context, in <-chan string, secrets map[string]string, workers int) <-chan BatchResult {
+	return utils.RunStream(ctx, in, workers, func(raw string) BatchResult {
+		return verifyItem(raw, secrets)
+	})
+}
+
+func verifyItem(raw string, secrets map[string]string) BatchResult {
+	p, ok := Verify(raw, secrets)
+	if !ok {
+		return BatchResult{Err: sign.ErrInvalidSignature}
+	}
+	return BatchResult{Params: p}
+}
@@ -0,0 +1,110 @@
+package vkma
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+// batchInput alternates valid and tampered launch strings.
+func batchInput(n int) []string {
+	raws := make([]string, n)
+	for i := range raws {
+		raws[i] = bytesQuery
+		if i%2 == 1 {
+			raws[i] = "vk_user_id=1&" + bytesQuery[len("vk_user_id=494075&"):]
+		}
+	}
+	return raws
+}
+
+func checkBatchResult(t *testing.T, i int, r BatchResult) {
+	t.Helper()
+	if i%2 == 0 {
+		if r.Err != nil || r.Params == nil || r.Params.VkUserID != 494075 {
+			t.Fatalf("result %d = %+v, want valid", i, r)
+		}
+	} else if !errors.Is(r.Err, sign.ErrInvalidSignature) || r.Params != nil {
+		t.Fatalf("result %d = %+v, want ErrInvalidSignature", i, r)
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	raws := batchInput(101)
+	results := VerifyBatch(context.Background(), raws, bytesSecrets, 4)
+	if len(results) != len(raws) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(raws))
+	}
+	for i, r := range results {
+		checkBatchResult(t, i, r)
+	}
+
+	if got := VerifyBatch(context.Background(), nil, bytesSecrets, 0); len(got) != 0 {
+		t.Errorf("VerifyBatch(nil) = %v, want empty", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i, r := range VerifyBatch(ctx, raws, bytesSecrets, 0) {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Fatalf("canceled result %d = %+v, want context.Canceled", i, r)
+		}
+	}
+}
+
+func TestVerifyStream(t *testing.T) {
+	raws := batchInput(101)
+	in := make(chan string)
+	go func() {
+		for _, raw := range raws {
+			in <- raw
+		}
+		close(in)
+	}()
+
+	n := 0
+	for r := range VerifyStream(context.Background(), in, bytesSecrets, 3) {
+		checkBatchResult(t, n, r)
+		n++
+	}
+	if n != len(raws) {
+		t.Errorf("received %d results, want %d", n, len(raws))
+	}
+}
+
+func TestVerifyStream_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan string)
+	out := VerifyStream(ctx, in, bytesSecrets, 2)
+
+	in <- bytesQuery
+	if r := <-out; r.Err != nil {
+		t.Fatalf("first result = %+v, want valid", r)
+	}
+
+	// The input is never closed; cancellation alone must close the output
+	cancel()
+	for range out {
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	raws := batchInput(1024)
+
+	b.Run("loop", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, raw := range raws {
+				_, _ = Verify(raw, bytesSecrets)
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = VerifyBatch(context.Background(), raws, bytesSecrets, 0)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package vkma
+
+import (
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// VerifyBytes is like Verify but reads the launch parameters straight
+// from raw, without copying them to a string first.
+//
+// String fields of the returned Params may point into raw and are only
+// valid while raw is not modified. Call Detach before raw is reused,
+// e.g. when it is a request buffer owned by the HTTP server.
+func VerifyBytes(raw []byte, secrets map[string]string) (*Params, bool) {
+	return Verify(utils.BytesToString(raw), secrets)
+}
+
+// Detach copies the string fields of p into memory owned by p, so that
+// they stay valid after the buffer passed to VerifyBytes is reused.
+// It allocates at most once.
+func (p *Params) Detach() {
+	utils.CloneStrings(
+		&p.VkLanguage,
+		(*string)(&p.VkRef),
+		&p.VkAccessTokenSettings,
+		(*string)(&p.VkViewerGroupRole),
+		(*string)(&p.VkPlatform),
+		&p.VkTs,
+		(*string)(&p.VkClient),
+		&p.Sign,
+	)
+}
@@ -0,0 +1,67 @@
+package vkma
+
+import (
+	"testing"
+)
+
+const bytesQuery = "vk_user_id=494075&vk_app_id=6736218&vk_is_app_user=1&vk_are_notifications_enabled=1&vk_language=ru&vk_access_token_settings=&vk_platform=andr%26oid&sign=gAgvKPEe3wJiC9ZdT16XuZ65_KSH5WkGSeDp_CQofws"
+
+var bytesSecrets = map[string]string{
+	"6736218": "wvl68m4dR1UpLrVRli",
+}
+
+func TestVerifyBytes(t *testing.T) {
+	want, ok := Verify(bytesQuery, bytesSecrets)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	if want.VkPlatform != "andr&oid" {
+		t.Fatalf("VkPlatform = %q, want %q", want.VkPlatform, "andr&oid")
+	}
+
+	raw := []byte(bytesQuery)
+	p, ok := VerifyBytes(raw, bytesSecrets)
+	if !ok || *p != *want {
+		t.Fatalf("VerifyBytes() = %+v, %v; want %+v, true", p, ok, want)
+	}
+
+	p.Detach()
+	for i := range raw {
+		raw[i] = 'x'
+	}
+	if *p != *want {
+		t.Errorf("after Detach and reuse = %+v, want %+v", p, want)
+	}
+
+	// Decoded values must not share the pooled unescape buffer
+	for i := 0; i < 4; i++ {
+		_, _ = Verify("vk_app_id=6736218&vk_platform=%78%78%78%78%78%78%78%78&sign=x", bytesSecrets)
+	}
+	if want.VkPlatform != "andr&oid" {
+		t.Errorf("VkPlatform after reuse = %q, want %q", want.VkPlatform, "andr&oid")
+	}
+}
+
+func BenchmarkVerifyBytes(b *testing.B) {
+	raw := []byte(bytesQuery)
+
+	b.Run("string", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = Verify(string(raw), bytesSecrets)
+		}
+	})
+	b.Run("bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = VerifyBytes(raw, bytesSecrets)
+		}
+	})
+	b.Run("bytes+detach", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			p, _ := VerifyBytes(raw, bytesSecrets)
+			p.Detach()
+		}
+	})
+}
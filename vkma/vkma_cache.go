@@ -0,0 +1,111 @@
+package vkma
+
+import (
+	"crypto/sha256"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// now returns the current time; tests replace it.
+var now = time.Now
+
+// cacheEntry is a verified result with a digest of the secret it was
+// verified with.
+type cacheEntry struct {
+	params Params
+	appID  string
+	secret [sha256.Size]byte
+}
+
+// Cache remembers successful verifications, so launch parameters that a
+// client sends with every API call are verified once per session.
+//
+// Entries are keyed by a SHA-256 digest of the raw launch parameters and
+// checked against a digest of the secret of their vk_app_id. They expire
+// after the TTL or, if a max age is set, once vk_ts is older than it,
+// whichever comes first. Failed verifications are not cached. A Cache is
+// safe for concurrent use.
+//
+// An entry takes about 330 bytes plus the strings of its Params, which are
+// at most as long as the raw launch parameters, so a full cache is bounded
+// by size times the input limit.
+type Cache struct {
+	// Limits bounds the input of verifications that miss the cache; nil
+	// means sign.DefaultLimits. Set it before the first call to Verify
+	Limits *sign.Limits
+
+	lru    *utils.LRU[cacheEntry]
+	ttl    time.Duration
+	maxAge time.Duration
+}
+
+// NewCache creates a cache of at most size entries. Entries live for ttl
+// and no longer than maxAge after their vk_ts; a non-positive ttl or
+// maxAge disables that bound.
+func NewCache(size int, ttl, maxAge time.Duration) *Cache {
+	return &Cache{lru: utils.NewLRU[cacheEntry](size), ttl: ttl, maxAge: maxAge}
+}
+
+// Verify is like Verify, but answers repeated launch parameters from the
+// cache. A cached entry is only used while secrets still maps its app ID
+// to the secret it was verified with.
+//
+// Every call returns a new *Params, so callers never share mutable state
+// through the cache. Like Verify, it does not reject stale launch
+// parameters; check VkTs as usual.
+func (c *Cache) Verify(rawQuery string, secrets map[string]string) (*Params, bool) {
+	t := now()
+	if e, ok := c.lru.Get(rawQuery, "", t.UnixNano()); ok {
+		if secret, ok := secrets[e.appID]; ok && sha256.Sum256([]byte(secret)) == e.secret {
+			p := new(Params)
+			*p = e.params // Only strings and values; see TestCache_Isolation
+			return p, true
+		}
+	}
+
+	params, ok := VerifyLimits(rawQuery, secrets, c.Limits)
+	if !ok {
+		return params, false
+	}
+
+	if expires, ok := c.expires(t, params.VkTs); ok {
+		appID := strconv.Itoa(params.VkAppID)
+		c.lru.Add(rawQuery, "", cacheEntry{params: *params, appID: appID, secret: sha256.Sum256([]byte(secrets[appID]))}, expires)
+	}
+	return params, true
+}
+
+// expires returns when an entry verified at t with the given vk_ts
+// leaves the cache, or false if it must not be cached.
+func (c *Cache) expires(t time.Time, vkTs string) (int64, bool) {
+	var exp time.Time // Zero: never expires
+	if c.ttl > 0 {
+		exp = t.Add(c.ttl)
+	}
+	if c.maxAge > 0 {
+		ts, err := strconv.ParseInt(vkTs, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		if e := time.Unix(ts, 0).Add(c.maxAge); exp.IsZero() || e.Before(exp) {
+			exp = e
+		}
+	}
+	if exp.IsZero() {
+		return math.MaxInt64, true
+	}
+	return exp.UnixNano(), exp.After(t)
+}
+
+// Stats returns the cache counters.
+func (c *Cache) Stats() sign.CacheStats {
+	hits, misses, expired, n := c.lru.Stats()
+	return sign.CacheStats{Hits: hits, Misses: misses, Expired: expired, Entries: n}
+}
+
+// Purge removes all entries.
+func (c *Cache) Purge() { c.lru.Purge() }
@@ -0,0 +1,74 @@
+package vkma
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestCache(t *testing.T) {
+	c := NewCache(16, time.Hour, 0)
+
+	p1, ok := c.Verify(bytesQuery, bytesSecrets)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	p2, ok := c.Verify(bytesQuery, bytesSecrets)
+	if !ok || p2 == p1 || *p2 != *p1 {
+		t.Fatalf("cached Verify() = %+v, %v; want a copy of %+v", p2, ok, p1)
+	}
+	p2.VkUserID = 1
+	if p3, _ := c.Verify(bytesQuery, bytesSecrets); p3.VkUserID != 494075 {
+		t.Error("modifying a returned Params changed the cache")
+	}
+	if got, want := c.Stats(), (sign.CacheStats{Hits: 2, Misses: 1, Entries: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	// A rotated secret invalidates cached entries
+	if _, ok := c.Verify(bytesQuery, map[string]string{"6736218": "rotated"}); ok {
+		t.Error("Verify() with rotated secret = true, want false")
+	}
+
+	// Without vk_ts nothing is cached when a max age is set
+	c = NewCache(16, 0, time.Minute)
+	c.Verify(bytesQuery, bytesSecrets)
+	if n := c.Stats().Entries; n != 0 {
+		t.Errorf("Entries without vk_ts = %d, want 0", n)
+	}
+}
+
+func TestCache_Isolation(t *testing.T) {
+	want, _ := Verify(bytesQuery, bytesSecrets)
+	c := NewCache(16, time.Hour, 0)
+
+	// Modify every field of the Params of a miss and of a hit
+	for i := 0; i < 2; i++ {
+		p, ok := c.Verify(bytesQuery, bytesSecrets)
+		if !ok {
+			t.Fatalf("Verify() #%d = false, want true", i+1)
+		}
+		*p = Params{
+			VkUserID: 1, VkAppID: 2, VkIsAppUser: !p.VkIsAppUser, VkAreNotificationsEnabled: !p.VkAreNotificationsEnabled,
+			VkIsFavorite: !p.VkIsFavorite, VkLanguage: "x", VkRef: "x", VkAccessTokenSettings: "x", VkGroupID: 3,
+			VkViewerGroupRole: "x", VkPlatform: "x", VkTs: "x", VkClient: "x", Sign: "x",
+		}
+	}
+
+	p, ok := c.Verify(bytesQuery, bytesSecrets)
+	if !ok || *p != *want {
+		t.Fatalf("Verify() after modifications = %+v, %v; want %+v", p, ok, want)
+	}
+	if st := c.Stats(); st.Hits != 2 || st.Misses != 1 {
+		t.Errorf("Stats() = %+v, want 2 hits, 1 miss", st)
+	}
+}
+
+func BenchmarkCache_Verify(b *testing.B) {
+	c := NewCache(1024, time.Hour, 0)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = c.Verify(bytesQuery, bytesSecrets)
+	}
+}
@@ -0,0 +1,43 @@
+package vkma
+
+import (
+	"strconv"
+
+	"github.com/elum-utils/sign"
+)
+
+// Scheme is the credential scheme of VK Mini App launch parameters.
+const Scheme = sign.SchemeVK
+
+// Identity builds a platform-agnostic identity from verified launch
+// parameters. Launch parameters carry no profile data, so only the user
+// ID, language and launch source (vk_ref) are set.
+func Identity(p *Params) *sign.Identity {
+	return &sign.Identity{
+		Platform: sign.VK,
+		UserID:   strconv.Itoa(p.VkUserID),
+		Language: p.VkLanguage,
+		Source:   string(p.VkRef),
+	}
+}
+
+// Verifier verifies launch parameters and implements sign.Verifier.
+type Verifier struct {
+	// Secrets maps application IDs to their secret keys
+	Secrets map[string]string
+
+	// Limits bounds the input; nil means sign.DefaultLimits
+	Limits *sign.Limits
+}
+
+// Scheme implements sign.Verifier.
+func (v *Verifier) Scheme() string { return Scheme }
+
+// Verify implements sign.Verifier.
+func (v *Verifier) Verify(raw string) (*sign.Identity, bool) {
+	p, ok := VerifyLimits(raw, v.Secrets, v.Limits)
+	if !ok || p.VkUserID == 0 {
+		return nil, false
+	}
+	return Identity(p), true
+}
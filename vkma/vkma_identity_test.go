@@ -0,0 +1,25 @@
+package vkma
+
+import (
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestVerifier(t *testing.T) {
+	raw := "q=1&vk_user_id=494075&vk_app_id=6736218&vk_is_app_user=1&vk_are_notifications_enabled=1&vk_language=ru&vk_access_token_settings=&vk_platform=andr%26oid&sign=gAgvKPEe3wJiC9ZdT16XuZ65_KSH5WkGSeDp_CQofws"
+
+	id, ok := (&Verifier{Secrets: map[string]string{"6736218": "wvl68m4dR1UpLrVRli"}}).Verify(raw)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	want := sign.Identity{Platform: sign.VK, UserID: "494075", Language: "ru"}
+	if *id != want {
+		t.Errorf("Identity = %+v, want %+v", *id, want)
+	}
+
+	if _, ok := (&Verifier{}).Verify(raw); ok {
+		t.Error("Verify() without secrets = true, want false")
+	}
+}
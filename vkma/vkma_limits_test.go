@@ -0,0 +1,66 @@
+package vkma
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+// many has more parameters than the insertion sort threshold, in reverse order.
+const many = "vk_app_id=6736218&vk_user_id=494075&vk_language=ru&vk_platform=desktop_web&vk_x20=20&vk_x19=19&vk_x18=18&vk_x17=17&vk_x16=16&vk_x15=15&vk_x14=14&vk_x13=13&vk_x12=12&vk_x11=11&vk_x10=10&vk_x09=9&vk_x08=8&vk_x07=7&vk_x06=6&vk_x05=5&sign=HhiOnlEFnvH2AQzdDupBU4t1Ae3tiMX1AY-l_eoOtx8"
+
+func TestVerify_Limits(t *testing.T) {
+	secrets := map[string]string{"6736218": "wvl68m4dR1UpLrVRli"}
+
+	tests := []struct {
+		name      string
+		rawQuery  string
+		wantValid bool
+	}{
+		{"Sorted above threshold", many, true},
+		{"Too long", many + "&q=" + strings.Repeat("a", 16<<10), false},
+		{"Too many parameters", strings.Repeat("q=1&", 64) + many, false},
+		{"Key too long", strings.Repeat("k", 65) + "=1&" + many, false},
+		{"Value too long", "q=" + strings.Repeat("v", 8<<10+1) + "&" + many, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Verify(tt.rawQuery, secrets); ok != tt.wantValid {
+				t.Errorf("Verify() = %v, want %v", ok, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestVerifyLimits(t *testing.T) {
+	secrets := map[string]string{"6736218": "wvl68m4dR1UpLrVRli"}
+
+	// many has 21 parameters
+	for _, tt := range []struct {
+		name   string
+		limits *sign.Limits
+		want   bool
+	}{
+		{"Default", nil, true},
+		{"Within", &sign.Limits{MaxParams: 21}, true},
+		{"Too many parameters", &sign.Limits{MaxParams: 20}, false},
+		{"Too long", &sign.Limits{MaxLength: len(many) - 1}, false},
+	} {
+		if _, ok := VerifyLimits(many, secrets, tt.limits); ok != tt.want {
+			t.Errorf("%s: VerifyLimits() = %v, want %v", tt.name, ok, tt.want)
+		}
+		if _, ok := (&Verifier{Secrets: secrets, Limits: tt.limits}).Verify(many); ok != tt.want {
+			t.Errorf("%s: Verifier.Verify() = %v, want %v", tt.name, ok, tt.want)
+		}
+		c := NewCache(4, 0, 0)
+		c.Limits = tt.limits
+		if _, ok := c.Verify(many, secrets); ok != tt.want {
+			t.Errorf("%s: Cache.Verify() = %v, want %v", tt.name, ok, tt.want)
+		}
+		if _, err := VerifyStrict(many, secrets, sign.StrictOptions{Limits: tt.limits}); (err == nil) != tt.want {
+			t.Errorf("%s: VerifyStrict() error = %v", tt.name, err)
+		}
+	}
+}
@@ -135,6 +135,47 @@ const (
 	MobileIPhoneMessenger  Platform = "mobile_iphone_messenger"   // iOS Messenger app
 )
 
+// Valid reports whether r is one of the known referral constants.
+func (r Referral) Valid() bool {
+	switch r {
+	case Catalog, CatalogRecent, CatalogFavourites, CatalogRecommendation,
+		CatalogTopDau, CatalogEntertainment, CatalogCommunication,
+		CatalogTools, CatalogShopping, CatalogEvents, CatalogEducation,
+		CatalogPayments, CatalogFinance, CatalogFood, CatalogHealth,
+		CatalogTravel, CatalogTaxi, CatalogJobs, CatalogRealty,
+		CatalogBusiness, CatalogLifestyle, CatalogAdmin, BoardTopicAll,
+		BoardTopicView, Feed, FeedPost, FeedComments, FeaturingDiscover,
+		FeaturingMenu, FeaturingNew, Fave, FaveLinks, FavePosts, Group,
+		GroupMenu, GroupMessages, GroupAddresses, SnippetPost, SnippetIm,
+		Clips, CommentsListClip, Im, ImChat, Notifications,
+		NotificationsGrouped, NotificationsAuto, SuperApp, HomeScreen, Menu,
+		SnippedPost, Story, StoryReply, StoryViewer, Profile, ArticleRead,
+		MusicPlaylist, VideoCarousel, PhotoBrowser, ShoppingCenter,
+		MarketItem, LeftNav, QuickSearch, Widget, Other, Showcase:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether r is one of the known role constants.
+func (r Role) Valid() bool {
+	switch r {
+	case RoleNone, RoleMember, RoleModer, RoleEditor, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether p is one of the known platform constants.
+func (p Platform) Valid() bool {
+	switch p {
+	case MobileAndroid, MobileIPhone, MobileWeb, DesktopWeb,
+		MobileAndroidMessenger, MobileIPhoneMessenger:
+		return true
+	}
+	return false
+}
+
 // Client represents the specific client application from which the app was launched.
 type Client string
 
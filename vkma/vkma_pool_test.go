@@ -0,0 +1,37 @@
+package vkma
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+)
+
+// signQuery appends the VK signature of the vk_* parameters in q made with secret.
+func signQuery(q url.Values, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(q.Encode()))
+	return q.Encode() + "&sign=" + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerify_PooledBuffers(t *testing.T) {
+	const secret = "wvl68m4dR1UpLrVRli"
+	secrets := map[string]string{"6736218": secret}
+	query := func(platform string) string {
+		return signQuery(url.Values{"vk_app_id": {"6736218"}, "vk_user_id": {"494075"}, "vk_platform": {platform}}, secret)
+	}
+
+	first, ok := Verify(query("andr&oid"), secrets)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	// A second verification reuses the pooled unescape buffer
+	if _, ok := Verify(query("ZZZZ&ZZZ"), secrets); !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	if first.VkPlatform != "andr&oid" {
+		t.Errorf("VkPlatform after reuse = %q, want %q", first.VkPlatform, "andr&oid")
+	}
+}
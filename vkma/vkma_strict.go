@@ -0,0 +1,67 @@
+package vkma
+
+import (
+	"strconv"
+
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// VerifyStrict validates launch parameters like Verify and additionally
+// rejects:
+//   - malformed and duplicate parameters
+//   - non-numeric and out-of-range IDs and timestamps, and flags other than "0" and "1"
+//   - vk_ref, vk_viewer_group_role and vk_platform values that are not
+//     known Referral, Role and Platform constants
+//   - unknown parameters, if opts.RejectUnknownKeys is set
+//
+// Parameter errors are reported as *sign.ParamError before the signature
+// is checked; a signature mismatch is reported as sign.ErrInvalidSignature.
+func VerifyStrict(rawQuery string, secrets map[string]string, opts sign.StrictOptions) (*Params, error) {
+	err := utils.StrictScan(rawQuery, opts.Limits, func(key, val string) error {
+		switch key {
+		case "vk_user_id", "vk_app_id", "vk_group_id", "vk_profile_id", "vk_testing_group_id":
+			// Parsed like Params.set, so out-of-range IDs are rejected
+			// instead of being read as zero
+			if _, err := strconv.Atoi(val); err != nil || !utils.IsDigits(val, false) {
+				return sign.ErrInvalidNumber
+			}
+		case "vk_ts":
+			if _, err := strconv.ParseInt(val, 10, 64); err != nil || !utils.IsDigits(val, false) {
+				return sign.ErrInvalidNumber
+			}
+		case "vk_is_app_user", "vk_are_notifications_enabled", "vk_is_favorite",
+			"vk_is_recommended", "vk_has_profile_button", "vk_is_widescreen":
+			if val != "0" && val != "1" {
+				return sign.ErrInvalidNumber
+			}
+		case "vk_ref":
+			if !Referral(val).Valid() {
+				return sign.ErrInvalidEnum
+			}
+		case "vk_viewer_group_role":
+			if !Role(val).Valid() {
+				return sign.ErrInvalidEnum
+			}
+		case "vk_platform":
+			if !Platform(val).Valid() {
+				return sign.ErrInvalidEnum
+			}
+		case "vk_language", "vk_access_token_settings", "vk_client", "vk_chat_id", "sign":
+		default:
+			if opts.RejectUnknownKeys {
+				return sign.ErrUnknownKey
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	params, ok := VerifyLimits(rawQuery, secrets, opts.Limits)
+	if !ok {
+		return nil, sign.ErrInvalidSignature
+	}
+	return params, nil
+}
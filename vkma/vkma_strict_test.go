@@ -0,0 +1,72 @@
+package vkma
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+const (
+	testStrict    = "vk_access_token_settings=&vk_app_id=6736218&vk_are_notifications_enabled=0&vk_is_app_user=1&vk_is_favorite=0&vk_language=ru&vk_platform=desktop_web&vk_ref=other&vk_ts=1700000000&vk_user_id=494075&sign=KgL3bhdEJLJ3CHdsGS7BwcBdJfCEY5W-m1t9YMBU2GM"
+	testDuplicate = "vk_access_token_settings=&vk_app_id=6736218&vk_are_notifications_enabled=0&vk_is_app_user=1&vk_is_favorite=0&vk_language=ru&vk_platform=desktop_web&vk_ref=other&vk_ts=1700000000&vk_user_id=494075&vk_user_id=1&sign=h6WZs3GIVkJI5fYORpH1bqWTyPE3DkU5OVM_5tBnZy4"
+)
+
+func TestVerifyStrict(t *testing.T) {
+	secrets := map[string]string{"6736218": "wvl68m4dR1UpLrVRli"}
+
+	// The duplicate is signed, so Verify accepts it with the last value
+	if p, ok := Verify(testDuplicate, secrets); !ok || p.VkUserID != 1 {
+		t.Fatalf("Verify() = %v, want true with VkUserID 1", ok)
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		opts    sign.StrictOptions
+		wantErr error
+		wantKey string
+	}{
+		{"Valid", testStrict, sign.StrictOptions{}, nil, ""},
+		{"Valid rejecting unknown keys", testStrict, sign.StrictOptions{RejectUnknownKeys: true}, nil, ""},
+		{"Unknown key allowed", "q=1&" + testStrict, sign.StrictOptions{}, nil, ""},
+		{"Unknown key rejected", "q=1&" + testStrict, sign.StrictOptions{RejectUnknownKeys: true}, sign.ErrUnknownKey, "q"},
+		{"Duplicate key", testDuplicate, sign.StrictOptions{}, sign.ErrDuplicateKey, "vk_user_id"},
+		{"Malformed pair", "vk_user_id&" + testStrict, sign.StrictOptions{}, sign.ErrMalformedPair, "vk_user_id"},
+		{"Non-numeric ID", "vk_group_id=12a&" + testStrict, sign.StrictOptions{}, sign.ErrInvalidNumber, "vk_group_id"},
+		{"Overflowing ID", "vk_user_id=99999999999999999999&" + testStrict, sign.StrictOptions{}, sign.ErrInvalidNumber, "vk_user_id"},
+		{"Invalid flag", "vk_is_favorite=yes&" + testStrict, sign.StrictOptions{}, sign.ErrInvalidNumber, "vk_is_favorite"},
+		{"Unknown referral", "vk_ref=nowhere", sign.StrictOptions{}, sign.ErrInvalidEnum, "vk_ref"},
+		{"Unknown role", "vk_viewer_group_role=owner", sign.StrictOptions{}, sign.ErrInvalidEnum, "vk_viewer_group_role"},
+		{"Unknown platform", "vk_platform=andr%26oid", sign.StrictOptions{}, sign.ErrInvalidEnum, "vk_platform"},
+		{"Bad signature", testStrict + "x", sign.StrictOptions{}, sign.ErrInvalidSignature, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := VerifyStrict(tt.raw, secrets, tt.opts)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("VerifyStrict() error = %v, want %v", err, tt.wantErr)
+			}
+			var pe *sign.ParamError
+			if tt.wantKey != "" && (!errors.As(err, &pe) || pe.Key != tt.wantKey) {
+				t.Errorf("ParamError = %v, want key %q", err, tt.wantKey)
+			}
+			if err == nil && p.VkUserID != 494075 {
+				t.Errorf("VkUserID = %d, want 494075", p.VkUserID)
+			}
+		})
+	}
+}
+
+func TestEnumValid(t *testing.T) {
+	if !Catalog.Valid() || !Showcase.Valid() || Referral("").Valid() {
+		t.Error("Referral.Valid() mismatch")
+	}
+	if !RoleAdmin.Valid() || Role("owner").Valid() {
+		t.Error("Role.Valid() mismatch")
+	}
+	if !MobileIPhoneMessenger.Valid() || Platform("mobile_symbian").Valid() {
+		t.Error("Platform.Valid() mismatch")
+	}
+}
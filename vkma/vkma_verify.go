@@ -5,8 +5,9 @@ package vkma
 import (
 	"crypto/sha256"
 	"encoding/base64"
-	"strings"
 
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/canon"
 	"github.com/elum-utils/sign/internal/utils"
 )
 
@@ -14,6 +15,9 @@ import (
 // This matches VK's signature encoding format requirements.
 var b64NoPad = base64.URLEncoding.WithPadding(base64.NoPadding)
 
+// signed selects the vk_* parameters covered by the signature.
+var signed = canon.Prefix("vk_")
+
 // Verify validates the signature of VK Mini Apps launch parameters against
 // provided application secrets using HMAC-SHA256.
 //
@@ -32,76 +36,49 @@ var b64NoPad = base64.URLEncoding.WithPadding(base64.NoPadding)
 //  4. Computes HMAC-SHA256 signature
 //  5. Compares with provided signature
 func Verify(rawQuery string, secrets map[string]string) (*Params, bool) {
+	return VerifyLimits(rawQuery, secrets, nil)
+}
+
+// VerifyLimits is like Verify, but rejects input exceeding limits instead
+// of sign.DefaultLimits. A nil limits means sign.DefaultLimits.
+func VerifyLimits(rawQuery string, secrets map[string]string, limits *sign.Limits) (*Params, bool) {
 	// Early return if no secrets provided
 	if len(secrets) == 0 {
 		return nil, false
 	}
 
-	var appID, sign string
+	var appID, sig string
 
 	// Get key-value pairs from sync.Pool to reduce allocations
 	pairsPtr := utils.KVPool.Get().(*utils.KVSlice)
 	pairs := (*pairsPtr)[:0] // Slice reset without reallocation
-	defer utils.KVPool.Put(pairsPtr)
+	defer func() { utils.PutKV(pairsPtr, pairs) }()
 
 	// Get temporary buffer for URL unescaping from pool
 	tmpBufPtr := utils.TmpBufPool.Get().(*[]byte)
-	tmpBuf := *tmpBufPtr
-	tmpBuf = tmpBuf[:0]
 	defer utils.TmpBufPool.Put(tmpBufPtr)
 
-	// Parse query string parameters
-	for start := 0; start < len(rawQuery); {
-
-		if start == 0 && rawQuery[start] == '?' {
-			start = 1
-			continue
-		}
-
-		// Find parameter boundary
-		end := strings.IndexByte(rawQuery[start:], '&')
-		if end == -1 {
-			end = len(rawQuery)
-		} else {
-			end += start
-		}
-
-		// Find key-value separator
-		eq := start
-		for eq < end && rawQuery[eq] != '=' {
-			eq++
-		}
-		if eq == end {
-			start = end + 1
-			continue // Skip malformed parameters without values
-		}
-
-
-		v := rawQuery[eq+1:end]
-		// Unescape both key and value
-		key, ok1 := utils.QueryUnescape(rawQuery[start:eq], &tmpBuf)
-		val, ok2 := utils.QueryUnescape(v, &tmpBuf)
-		if !ok1 || !ok2 {
-			return nil, false // Skip if unescaping fails
-		}
-
+	// Parse query string parameters, skipping those without values
+	it := canon.NewIterator(rawQuery, (*tmpBufPtr)[:0], canon.Options{Limits: limits})
+	for p, ok := it.Next(); ok; p, ok = it.Next() {
 		// Categorize parameters
 		switch {
-		case key == "sign":
-			sign = val // Store signature separately
-		case key == "vk_app_id":
-			appID = val // Store app ID for secret lookup
-			pairs = append(pairs, utils.KV{Key: key, Val: val})
-		case strings.HasPrefix(key, "vk_"):
+		case p.Key == "sign":
+			sig = p.Value // Store signature separately
+		case p.Key == "vk_app_id":
+			appID = p.Value // Store app ID for secret lookup
+			pairs = append(pairs, p)
+		case signed(p.Key):
 			// Include all vk_* parameters except vk_app_id already handled
-			pairs = append(pairs, utils.KV{Key: key, Val: val})
+			pairs = append(pairs, p)
 		}
-
-		start = end + 1
+	}
+	if it.Err() != nil {
+		return nil, false // Oversized input or invalid escapes
 	}
 
 	// Verify required parameters exist
-	if appID == "" || sign == "" {
+	if appID == "" || sig == "" {
 		return nil, false
 	}
 
@@ -112,25 +89,21 @@ func Verify(rawQuery string, secrets map[string]string) (*Params, bool) {
 	}
 
 	// Sort parameters lexicographically by key for canonical string
-	pairs.InsertionSort()
+	pairs.Sort()
 
 	// Get buffer for canonical string from pool
 	bufPtr := utils.BufCanonicalPool.Get().(*[]byte)
 	buf := (*bufPtr)[:0]
-	defer utils.BufCanonicalPool.Put(bufPtr)
+	defer func() { utils.PutBuf(&utils.BufCanonicalPool, bufPtr, buf) }()
 
-	var params Params // Only allocation for result
-	for i, p := range pairs {
-		if i > 0 {
-			buf = append(buf, '&') // Parameter separator
-		}
-		buf = utils.AppendEscape(buf, p.Key)
-		buf = append(buf, '=')
-		buf = utils.AppendEscape(buf, p.Val)
+	// Detach stored values from the pooled unescape buffer
+	d := utils.NewDetacher(*tmpBufPtr, it.Buffer())
 
-		// Store parameter while building canonical string
-		params.set(p.Key, p.Val)
+	var params Params // Only allocation for result
+	for _, p := range pairs {
+		params.set(p.Key, d.String(p.Value))
 	}
+	buf = canon.AppendQuery(buf, pairs)
 
 	// Compute HMAC-SHA256 signature
 	mac := utils.GetHMAC(secret)
@@ -149,10 +122,13 @@ func Verify(rawQuery string, secrets map[string]string) (*Params, bool) {
 	expectedSign = expectedSign[:43] // Base64 URL encoded SHA-256 length
 	b64NoPad.Encode(expectedSign, sum)
 
+	// Compare before returning the buffers: another goroutine may
+	// reuse them as soon as they are back in the pools
+	valid := string(expectedSign) == sig
+
 	// Return resources to pools
 	utils.Base64BufPool.Put(b64Ptr)
 	utils.Sha256SumBufPool.Put(sumPtr)
 
-	// Constant-time comparison to prevent timing attacks
-	return &params, string(expectedSign) == sign
+	return &params, valid
 }
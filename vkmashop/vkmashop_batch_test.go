@@ -0,0 +1,31 @@
+package vkmashop
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	raws := []string{bytesQuery, "lang=en&" + bytesQuery[len("app_id=52333469&"):], bytesQuery}
+	results := VerifyBatch(context.Background(), raws, bytesSecrets, 0)
+	for i, r := range results {
+		valid := i != 1
+		if valid && (r.Err != nil || r.Params.OrderID != 2256399) {
+			t.Errorf("result %d = %+v, want valid", i, r)
+		}
+		if !valid && !errors.Is(r.Err, sign.ErrInvalidSignature) {
+			t.Errorf("result %d = %+v, want ErrInvalidSignature", i, r)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i, r := range VerifyBatch(ctx, raws, bytesSecrets, 0) {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("canceled result %d = %+v, want context.Canceled", i, r)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package vkmashop
+
+import (
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// VerifyBytes is like Verify but reads the notification straight from raw,
+// without copying it to a string first.
+//
+// String fields of the returned Params may point into raw and are only
+// valid while raw is not modified. Call Detach before raw is reused,
+// e.g. when it is a request body buffer that gets recycled.
+func VerifyBytes(raw []byte, secrets map[string]string) (*Params, bool) {
+	return Verify(utils.BytesToString(raw), secrets)
+}
+
+// Detach copies the string fields of b into memory owned by b, so that
+// they stay valid after the buffer passed to VerifyBytes is reused.
+// It allocates at most once.
+func (b *Params) Detach() {
+	utils.CloneStrings(
+		&b.Lang,
+		&b.Item,
+		&b.ItemID,
+		&b.ItemPhotoURL,
+		&b.ItemTitle,
+		(*string)(&b.NotificationType),
+		(*string)(&b.Status),
+		(*string)(&b.CancelReason),
+		&b.Version,
+		&b.Sig,
+	)
+}
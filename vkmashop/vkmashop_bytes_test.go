@@ -0,0 +1,59 @@
+package vkmashop
+
+import (
+	"testing"
+)
+
+const bytesQuery = "app_id=52333469&item=Subscribtion_Item_NoAd30&lang=ru_RU&notification_type=get_item_test&order_id=2256399&receiver_id=262959639&user_id=262959639&sig=871447748e3803be83acb30dec37b5e5"
+
+var bytesSecrets = map[string]string{
+	"52333469": "5STCdDl55VezBzYt0AUA",
+}
+
+func TestVerifyBytes(t *testing.T) {
+	want, ok := Verify(bytesQuery, bytesSecrets)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	raw := []byte(bytesQuery)
+	p, ok := VerifyBytes(raw, bytesSecrets)
+	if !ok || *p != *want {
+		t.Fatalf("VerifyBytes() = %+v, %v; want %+v, true", p, ok, want)
+	}
+
+	p.Detach()
+	for i := range raw {
+		raw[i] = 'x'
+	}
+	if *p != *want {
+		t.Errorf("after Detach and reuse = %+v, want %+v", p, want)
+	}
+	if _, ok := VerifyBytes(raw, bytesSecrets); ok {
+		t.Error("VerifyBytes() on reused buffer = true, want false")
+	}
+}
+
+func BenchmarkVerifyBytes(b *testing.B) {
+	raw := []byte(bytesQuery)
+
+	b.Run("string", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = Verify(string(raw), bytesSecrets)
+		}
+	})
+	b.Run("bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = VerifyBytes(raw, bytesSecrets)
+		}
+	})
+	b.Run("bytes+detach", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			p, _ := VerifyBytes(raw, bytesSecrets)
+			p.Detach()
+		}
+	})
+}
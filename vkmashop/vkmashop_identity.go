@@ -0,0 +1,41 @@
+package vkmashop
+
+import (
+	"strconv"
+
+	"github.com/elum-utils/sign"
+)
+
+// Scheme is the credential scheme of VK payment notifications.
+const Scheme = sign.SchemeVKShop
+
+// Identity builds a platform-agnostic identity of the user a verified
+// payment notification is about.
+func Identity(p *Params) *sign.Identity {
+	return &sign.Identity{
+		Platform: sign.VK,
+		UserID:   strconv.Itoa(p.UserID),
+		Language: p.Lang,
+	}
+}
+
+// Verifier verifies payment notifications and implements sign.Verifier.
+type Verifier struct {
+	// Secrets maps application IDs to their secret keys
+	Secrets map[string]string
+
+	// Limits bounds the input; nil means sign.DefaultLimits
+	Limits *sign.Limits
+}
+
+// Scheme implements sign.Verifier.
+func (v *Verifier) Scheme() string { return Scheme }
+
+// Verify implements sign.Verifier.
+func (v *Verifier) Verify(raw string) (*sign.Identity, bool) {
+	p, ok := VerifyLimits(raw, v.Secrets, v.Limits)
+	if !ok || p.UserID == 0 {
+		return nil, false
+	}
+	return Identity(p), true
+}
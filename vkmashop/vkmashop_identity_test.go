@@ -0,0 +1,21 @@
+package vkmashop
+
+import (
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestVerifier(t *testing.T) {
+	raw := "app_id=52333469&item=Subscribtion_Item_NoAd30&lang=ru_RU&notification_type=get_item_test&order_id=2256399&receiver_id=262959639&user_id=262959639&sig=871447748e3803be83acb30dec37b5e5"
+
+	id, ok := (&Verifier{Secrets: map[string]string{"52333469": "5STCdDl55VezBzYt0AUA"}}).Verify(raw)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	want := sign.Identity{Platform: sign.VK, UserID: "262959639", Language: "ru_RU"}
+	if *id != want {
+		t.Errorf("Identity = %+v, want %+v", *id, want)
+	}
+}
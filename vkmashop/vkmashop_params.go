@@ -34,6 +34,34 @@ const (
 	CancelUnknown      CancelReason = "unknown"
 )
 
+// Valid reports whether s is one of the known status constants.
+func (s Status) Valid() bool {
+	switch s {
+	case Chargeable, Canceled, Refunded, Active:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether t is one of the known notification type constants.
+func (t NotificationType) Valid() bool {
+	switch t {
+	case GetItem, GetItemTest, OrderStatusChange, OrderStatusChangeTest,
+		GetSubscription, SubscriptionStatusChange, SubscriptionStatusChangeTest:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether r is one of the known cancel reason constants.
+func (r CancelReason) Valid() bool {
+	switch r {
+	case CancelUserDecision, CancelAppDecision, CancelPaymentFail, CancelUnknown:
+		return true
+	}
+	return false
+}
+
 // Params represents the payment notification parameters sent by VK's payment system.
 // These parameters are received via HTTP POST when a payment event occurs in your VK Mini App.
 //
@@ -0,0 +1,46 @@
+package vkmashop
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// signQuery appends the VK Shop signature of rawQuery made with secret.
+func signQuery(rawQuery, secret string) string {
+	q, _ := url.ParseQuery(rawQuery)
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + "=" + q.Get(k))
+	}
+	b.WriteString(secret)
+	sum := md5.Sum([]byte(b.String()))
+	return rawQuery + "&sig=" + hex.EncodeToString(sum[:])
+}
+
+func TestVerify_PooledBuffers(t *testing.T) {
+	const secret = "5STCdDl55VezBzYt0AUA"
+	secrets := map[string]string{"52333469": secret}
+
+	first, ok := Verify(signQuery("app_id=52333469&item_title=Item+one&user_id=1", secret), secrets)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	// A second verification reuses the pooled unescape buffer
+	if _, ok := Verify(signQuery("app_id=52333469&item_title=ZZZZ+ZZZ&user_id=1", secret), secrets); !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+	if first.ItemTitle != "Item one" {
+		t.Errorf("ItemTitle after reuse = %q, want %q", first.ItemTitle, "Item one")
+	}
+}
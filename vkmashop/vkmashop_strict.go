@@ -0,0 +1,60 @@
+package vkmashop
+
+import (
+	"strconv"
+
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// VerifyStrict validates a notification like Verify and additionally
+// rejects:
+//   - malformed and duplicate parameters
+//   - non-numeric and out-of-range values of numeric fields, which Verify
+//     reads as zero
+//   - notification_type, status and cancel_reason values that are not
+//     known NotificationType, Status and CancelReason constants
+//   - unknown parameters, if opts.RejectUnknownKeys is set
+//
+// Parameter errors are reported as *sign.ParamError before the signature
+// is checked; a signature mismatch is reported as sign.ErrInvalidSignature.
+func VerifyStrict(rawQuery string, secrets map[string]string, opts sign.StrictOptions) (*Params, error) {
+	err := utils.StrictScan(rawQuery, opts.Limits, func(key, val string) error {
+		switch key {
+		case "app_id", "user_id", "date", "item_discount", "item_price",
+			"subscription_id", "order_id", "receiver_id":
+			// Parsed like Params.set, so out-of-range values are rejected
+			// instead of being read as zero
+			if _, err := strconv.Atoi(val); err != nil || !utils.IsDigits(val, false) {
+				return sign.ErrInvalidNumber
+			}
+		case "notification_type":
+			if !NotificationType(val).Valid() {
+				return sign.ErrInvalidEnum
+			}
+		case "status":
+			if !Status(val).Valid() {
+				return sign.ErrInvalidEnum
+			}
+		case "cancel_reason":
+			if !CancelReason(val).Valid() {
+				return sign.ErrInvalidEnum
+			}
+		case "lang", "item", "item_id", "item_photo_url", "item_title", "version", "sig":
+		default:
+			if opts.RejectUnknownKeys {
+				return sign.ErrUnknownKey
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	params, ok := VerifyLimits(rawQuery, secrets, opts.Limits)
+	if !ok {
+		return nil, sign.ErrInvalidSignature
+	}
+	return params, nil
+}
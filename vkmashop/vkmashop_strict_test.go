@@ -0,0 +1,68 @@
+package vkmashop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestVerifyStrict(t *testing.T) {
+	secrets := map[string]string{"52333469": "5STCdDl55VezBzYt0AUA"}
+	raw := "app_id=52333469&item=Subscribtion_Item_NoAd30&lang=ru_RU&notification_type=get_item_test&order_id=2256399&receiver_id=262959639&user_id=262959639&sig=871447748e3803be83acb30dec37b5e5"
+
+	tests := []struct {
+		name    string
+		raw     string
+		opts    sign.StrictOptions
+		wantErr error
+	}{
+		{"Valid", raw, sign.StrictOptions{RejectUnknownKeys: true}, nil},
+		{"Unknown key rejected", "extra=1&" + raw, sign.StrictOptions{RejectUnknownKeys: true}, sign.ErrUnknownKey},
+		{"Duplicate key", "user_id=1&" + raw, sign.StrictOptions{}, sign.ErrDuplicateKey},
+		{"Non-numeric price", "item_price=10.5&" + raw, sign.StrictOptions{}, sign.ErrInvalidNumber},
+		{"Empty number", "date=&" + raw, sign.StrictOptions{}, sign.ErrInvalidNumber},
+		{"Overflowing number", "item_price=99999999999999999999&" + raw, sign.StrictOptions{}, sign.ErrInvalidNumber},
+		{"Unknown notification type", "notification_type=refund", sign.StrictOptions{}, sign.ErrInvalidEnum},
+		{"Unknown status", "status=paid", sign.StrictOptions{}, sign.ErrInvalidEnum},
+		{"Unknown cancel reason", "cancel_reason=bored", sign.StrictOptions{}, sign.ErrInvalidEnum},
+		{"Bad signature", "version=5.131&" + raw, sign.StrictOptions{}, sign.ErrInvalidSignature},
+		{"Custom limits", raw, sign.StrictOptions{Limits: &sign.Limits{MaxParams: 7}}, sign.ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := VerifyStrict(tt.raw, secrets, tt.opts)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("VerifyStrict() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && p.NotificationType != GetItemTest {
+				t.Errorf("NotificationType = %q, want %q", p.NotificationType, GetItemTest)
+			}
+		})
+	}
+}
+
+func TestVerifyLimits(t *testing.T) {
+	secrets := map[string]string{"52333469": "5STCdDl55VezBzYt0AUA"}
+	raw := "app_id=52333469&item=Subscribtion_Item_NoAd30&lang=ru_RU&notification_type=get_item_test&order_id=2256399&receiver_id=262959639&user_id=262959639&sig=871447748e3803be83acb30dec37b5e5"
+
+	// raw has eight parameters
+	for _, tt := range []struct {
+		name   string
+		limits *sign.Limits
+		want   bool
+	}{
+		{"Default", nil, true},
+		{"Within", &sign.Limits{MaxParams: 8}, true},
+		{"Too many parameters", &sign.Limits{MaxParams: 7}, false},
+		{"Key too long", &sign.Limits{MaxKeyLength: 10}, false},
+	} {
+		if _, ok := VerifyLimits(raw, secrets, tt.limits); ok != tt.want {
+			t.Errorf("%s: VerifyLimits() = %v, want %v", tt.name, ok, tt.want)
+		}
+		if _, ok := (&Verifier{Secrets: secrets, Limits: tt.limits}).Verify(raw); ok != tt.want {
+			t.Errorf("%s: Verifier.Verify() = %v, want %v", tt.name, ok, tt.want)
+		}
+	}
+}
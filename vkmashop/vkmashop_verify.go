@@ -4,8 +4,9 @@ package vkmashop
 
 import (
 	"crypto/md5"
-	"strings"
 
+	"github.com/elum-utils/sign"
+	"github.com/elum-utils/sign/canon"
 	"github.com/elum-utils/sign/internal/utils"
 )
 
@@ -26,6 +27,12 @@ import (
 //   4. Computes MD5 hash
 //   5. Compares with provided signature without string allocations
 func Verify(rawQuery string, secrets map[string]string) (*Params, bool) {
+	return VerifyLimits(rawQuery, secrets, nil)
+}
+
+// VerifyLimits is like Verify, but rejects input exceeding limits instead
+// of sign.DefaultLimits. A nil limits means sign.DefaultLimits.
+func VerifyLimits(rawQuery string, secrets map[string]string, limits *sign.Limits) (*Params, bool) {
 	// Early return if no secrets provided
 	if len(secrets) == 0 {
 		return nil, false
@@ -36,53 +43,29 @@ func Verify(rawQuery string, secrets map[string]string) (*Params, bool) {
 	// Get key-value pairs from sync.Pool to reduce allocations
 	pairsPtr := utils.KVPool.Get().(*utils.KVSlice)
 	pairs := (*pairsPtr)[:0] // Slice reset without reallocation
-	defer utils.KVPool.Put(pairsPtr)
+	defer func() { utils.PutKV(pairsPtr, pairs) }()
 
 	// Get temporary buffer for URL unescaping from pool
 	tmpBufPtr := utils.TmpBufPool.Get().(*[]byte)
-	tmpBuf := *tmpBufPtr
 	defer utils.TmpBufPool.Put(tmpBufPtr)
 
-	// Parse query string parameters
-	for start := 0; start < len(rawQuery); {
-		// Find parameter boundary
-		end := strings.IndexByte(rawQuery[start:], '&')
-		if end == -1 {
-			end = len(rawQuery)
-		} else {
-			end += start
-		}
-
-		// Find key-value separator
-		eq := start
-		for eq < end && rawQuery[eq] != '=' {
-			eq++
-		}
-		if eq == end {
-			start = end + 1
-			continue // Skip malformed parameters without values
-		}
-
-		// Unescape both key and value
-		key, ok1 := utils.QueryUnescape(rawQuery[start:eq], &tmpBuf)
-		val, ok2 := utils.QueryUnescape(rawQuery[eq+1:end], &tmpBuf)
-		if !ok1 || !ok2 {
-			return nil, false // Skip if unescaping fails
-		}
-
+	// Parse query string parameters, skipping those without values
+	it := canon.NewIterator(rawQuery, (*tmpBufPtr)[:0], canon.Options{Limits: limits})
+	for p, ok := it.Next(); ok; p, ok = it.Next() {
 		// Categorize parameters
-		switch key {
+		switch p.Key {
 		case "app_id":
-			appID = val // Store app ID for secret lookup
-			pairs = append(pairs, utils.KV{Key: key, Val: val})
+			appID = p.Value // Store app ID for secret lookup
+			pairs = append(pairs, p)
 		case "sig":
-			sig = val // Store signature separately
+			sig = p.Value // Store signature separately
 		default:
 			// Include all other parameters in verification
-			pairs = append(pairs, utils.KV{Key: key, Val: val})
+			pairs = append(pairs, p)
 		}
-
-		start = end + 1
+	}
+	if it.Err() != nil {
+		return nil, false // Oversized input or invalid escapes
 	}
 
 	// Verify required parameters exist
@@ -97,25 +80,24 @@ func Verify(rawQuery string, secrets map[string]string) (*Params, bool) {
 	}
 
 	// Sort parameters lexicographically by key
-	pairs.InsertionSort()
+	pairs.Sort()
 
 	// Get buffer for signature string from pool
 	bufPtr := utils.BufCanonicalPool.Get().(*[]byte)
 	buf := (*bufPtr)[:0]
-	defer utils.BufCanonicalPool.Put(bufPtr)
+	defer func() { utils.PutBuf(&utils.BufCanonicalPool, bufPtr, buf) }()
+
+	// Detach stored values from the pooled unescape buffer
+	d := utils.NewDetacher(*tmpBufPtr, it.Buffer())
 
 	body := &Params{} // Only allocation for result
 	for _, p := range pairs {
-		// Build signature string format: key=value
-		buf = append(buf, p.Key...)
-		buf = append(buf, '=')
-		buf = append(buf, p.Val...)
-		
-		// Store parameter while building signature string
-		body.set(p.Key, p.Val)
+		body.set(p.Key, d.String(p.Value))
 	}
-	// Append secret key as specified in VK Shop docs
-	buf = append(buf, secret...)
+
+	// Signature string format: key=value pairs followed by the secret
+	// key, as specified in VK Shop docs
+	buf = canon.AppendConcatSecret(buf, pairs, secret)
 
 	// Compute MD5 hash of the signature string
 	sum := md5.Sum(buf)
@@ -54,6 +54,19 @@ func TestVerify(t *testing.T) {
 			clientSecrets: secrets,
 			wantValid:     true,
 		},
+		{
+			name: "Leading question mark",
+			rawQuery: "?app_id=52333469" +
+				"&item=Subscribtion_Item_NoAd30" +
+				"&lang=ru_RU" +
+				"&notification_type=get_item_test" +
+				"&order_id=2256399" +
+				"&receiver_id=262959639" +
+				"&user_id=262959639" +
+				"&sig=871447748e3803be83acb30dec37b5e5",
+			clientSecrets: secrets,
+			wantValid:     true,
+		},
 	}
 
 	for _, tt := range tests {
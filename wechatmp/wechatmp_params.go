@@ -0,0 +1,52 @@
+// Package wechatmp provides types for the user data a WeChat mini program
+// sends to its backend: the plain rawData payload and the payloads of
+// encryptedData for user info and phone numbers.
+package wechatmp
+
+// Watermark identifies the mini program and the time an encrypted payload
+// was produced. It must be checked to bind the payload to the app.
+type Watermark struct {
+	// AppID is the mini program appid the payload was produced for
+	AppID string `json:"appid"`
+
+	// Timestamp is the Unix time the payload was produced
+	Timestamp int64 `json:"timestamp"`
+}
+
+// RawUser is the public profile sent as rawData by wx.getUserInfo.
+type RawUser struct {
+	NickName  string `json:"nickName"`
+	Gender    int    `json:"gender"` // 0 unknown, 1 male, 2 female
+	Language  string `json:"language"`
+	City      string `json:"city"`
+	Province  string `json:"province"`
+	Country   string `json:"country"`
+	AvatarURL string `json:"avatarUrl"`
+}
+
+// User is the decrypted user info payload of wx.getUserInfo.
+type User struct {
+	RawUser
+
+	// OpenID is the user's ID within the mini program
+	OpenID string `json:"openId"`
+
+	// UnionID is the user's ID across apps of the same developer account
+	UnionID string `json:"unionId"`
+
+	Watermark Watermark `json:"watermark"`
+}
+
+// PhoneNumber is the decrypted payload of the getPhoneNumber button.
+type PhoneNumber struct {
+	// PhoneNumber is the number with country code (e.g. "+86 13800000000")
+	PhoneNumber string `json:"phoneNumber"`
+
+	// PurePhoneNumber is the number without country code
+	PurePhoneNumber string `json:"purePhoneNumber"`
+
+	// CountryCode is the country calling code (e.g. "86")
+	CountryCode string `json:"countryCode"`
+
+	Watermark Watermark `json:"watermark"`
+}
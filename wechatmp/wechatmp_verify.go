@@ -0,0 +1,159 @@
+// Package wechatmp provides functionality for verifying WeChat mini program
+// user data: the SHA-1 signature of rawData and the AES-128-CBC encrypted
+// encryptedData payloads, both keyed with the user's session_key.
+//
+// Obtaining the session_key (code2Session) is out of scope; it is passed in.
+package wechatmp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// now returns the current time; replaced in tests.
+var now = time.Now
+
+// VerifyRawData validates the signature of rawData and decodes the profile.
+//
+// Parameters:
+//   - rawData: The rawData string returned by wx.getUserInfo
+//   - signature: The signature string returned alongside rawData
+//   - sessionKey: The user's session_key (base64, as returned by WeChat)
+//
+// Returns:
+//   - *RawUser: Decoded profile if verification succeeds
+//   - bool: true if signature is valid, false otherwise
+//
+// The signature is the hex SHA-1 of rawData followed by sessionKey,
+// compared in constant time.
+func VerifyRawData(rawData, signature, sessionKey string) (*RawUser, bool) {
+	if rawData == "" || sessionKey == "" || len(signature) != sha1.Size*2 {
+		return nil, false
+	}
+
+	h := sha1.New()
+	h.Write([]byte(rawData))
+	h.Write([]byte(sessionKey))
+
+	var sum [sha1.Size]byte
+	var expected [sha1.Size * 2]byte
+	hex.Encode(expected[:], h.Sum(sum[:0]))
+
+	if subtle.ConstantTimeCompare(expected[:], []byte(signature)) != 1 {
+		return nil, false
+	}
+
+	var user RawUser
+	if err := json.Unmarshal([]byte(rawData), &user); err != nil {
+		return nil, false
+	}
+	return &user, true
+}
+
+// DecryptUser decrypts the encryptedData of wx.getUserInfo and checks
+// its watermark.
+//
+// Parameters:
+//   - encryptedData, iv: The base64 values returned by the client
+//   - sessionKey: The user's session_key (base64)
+//   - appID: The mini program appid the watermark must match
+//   - maxAge: Maximum age of the watermark timestamp; zero disables the check
+//
+// Returns:
+//   - *User: Decrypted user info if decryption and checks succeed
+//   - bool: true on success, false otherwise
+func DecryptUser(encryptedData, iv, sessionKey, appID string, maxAge time.Duration) (*User, bool) {
+	var user User
+	if !decrypt(encryptedData, iv, sessionKey, &user) {
+		return nil, false
+	}
+	if !checkWatermark(user.Watermark, appID, maxAge) {
+		return nil, false
+	}
+	return &user, true
+}
+
+// DecryptPhoneNumber decrypts the encryptedData of the getPhoneNumber
+// button and checks its watermark.
+//
+// Parameters are the same as for DecryptUser.
+func DecryptPhoneNumber(encryptedData, iv, sessionKey, appID string, maxAge time.Duration) (*PhoneNumber, bool) {
+	var phone PhoneNumber
+	if !decrypt(encryptedData, iv, sessionKey, &phone) {
+		return nil, false
+	}
+	if !checkWatermark(phone.Watermark, appID, maxAge) {
+		return nil, false
+	}
+	return &phone, true
+}
+
+// Decrypt decrypts encryptedData and returns the plaintext JSON without
+// interpreting it, for payloads not covered by the typed helpers.
+// The caller is responsible for checking the watermark.
+func Decrypt(encryptedData, iv, sessionKey string) ([]byte, bool) {
+	key, err := base64.StdEncoding.DecodeString(sessionKey)
+	if err != nil || len(key) != 16 {
+		return nil, false
+	}
+	ivBytes, err := base64.StdEncoding.DecodeString(iv)
+	if err != nil || len(ivBytes) != aes.BlockSize {
+		return nil, false
+	}
+	data, err := base64.StdEncoding.DecodeString(encryptedData)
+	if err != nil || len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, false
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, false
+	}
+	cipher.NewCBCDecrypter(block, ivBytes).CryptBlocks(data, data)
+
+	return unpad(data)
+}
+
+// decrypt decrypts encryptedData and decodes the JSON plaintext into v.
+func decrypt(encryptedData, iv, sessionKey string, v any) bool {
+	plain, ok := Decrypt(encryptedData, iv, sessionKey)
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(plain, v) == nil
+}
+
+// unpad removes PKCS#7 padding. WeChat pads to the AES block size,
+// so the pad length must be between 1 and 16 with all bytes equal.
+func unpad(data []byte) ([]byte, bool) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > aes.BlockSize || n > len(data) {
+		return nil, false
+	}
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, false
+		}
+	}
+	return data[:len(data)-n], true
+}
+
+// checkWatermark reports whether w belongs to appID and is fresh enough.
+func checkWatermark(w Watermark, appID string, maxAge time.Duration) bool {
+	if appID == "" || subtle.ConstantTimeCompare([]byte(w.AppID), []byte(appID)) != 1 {
+		return false
+	}
+	if maxAge > 0 {
+		age := now().Sub(time.Unix(w.Timestamp, 0))
+		if age > maxAge || age < -maxAge {
+			return false
+		}
+	}
+	return true
+}
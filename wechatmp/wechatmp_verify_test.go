@@ -0,0 +1,157 @@
+package wechatmp
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+const (
+	testAppID      = "wx4f4bc4dec97d474b"
+	testSessionKey = "tiihtNczf5v6AKRyjwEUhQ=="
+	testIV         = "r7BXXKkLb8qrSNn05n0qiA=="
+)
+
+// encrypt mirrors WeChat's encryption of encryptedData for tests.
+func encrypt(t testing.TB, plain string) string {
+	key, _ := base64.StdEncoding.DecodeString(testSessionKey)
+	iv, _ := base64.StdEncoding.DecodeString(testIV)
+
+	n := aes.BlockSize - len(plain)%aes.BlockSize
+	data := append([]byte(plain), bytes.Repeat([]byte{byte(n)}, n)...)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func sign(rawData string) string {
+	sum := sha1.Sum([]byte(rawData + testSessionKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerifyRawData(t *testing.T) {
+	rawData := `{"nickName":"Band","gender":1,"language":"zh_CN","city":"Guangzhou","province":"Guangdong","country":"CN","avatarUrl":"http://wx.qlogo.cn/a.png"}`
+
+	tests := []struct {
+		name       string
+		rawData    string
+		signature  string
+		sessionKey string
+		wantValid  bool
+	}{
+		{"Missing session key", rawData, sign(rawData), "", false},
+		{"Short signature", rawData, "abc", testSessionKey, false},
+		{"Wrong signature", rawData, sign(rawData + " "), testSessionKey, false},
+		{"Invalid JSON", "not json", sign("not json"), testSessionKey, false},
+		{"Valid", rawData, sign(rawData), testSessionKey, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, ok := VerifyRawData(tt.rawData, tt.signature, tt.sessionKey)
+			if ok != tt.wantValid {
+				t.Fatalf("VerifyRawData() = %v, want %v", ok, tt.wantValid)
+			}
+			if ok && (u.NickName != "Band" || u.Gender != 1 || u.City != "Guangzhou") {
+				t.Errorf("unexpected user: %+v", u)
+			}
+		})
+	}
+}
+
+func TestDecryptUser(t *testing.T) {
+	now = func() time.Time { return time.Unix(1700000100, 0) }
+	defer func() { now = time.Now }()
+
+	valid := encrypt(t, `{"openId":"oGZUI0egBJY1zhBYw2KhdUfwVJJE","nickName":"Band","gender":1,"unionId":"ocMvos6NjeKLIBqg5Mr9QjxrP1FA","watermark":{"timestamp":1700000000,"appid":"wx4f4bc4dec97d474b"}}`)
+	otherApp := encrypt(t, `{"openId":"x","watermark":{"timestamp":1700000000,"appid":"wx0000000000000000"}}`)
+
+	tests := []struct {
+		name          string
+		encryptedData string
+		iv            string
+		sessionKey    string
+		maxAge        time.Duration
+		wantValid     bool
+	}{
+		{"Invalid base64", "###", testIV, testSessionKey, 0, false},
+		{"Wrong key length", valid, testIV, "c2hvcnQ=", 0, false},
+		{"Wrong IV", valid, "AAAAAAAAAAAAAAAAAAAAAA==", testSessionKey, 0, false},
+		{"Wrong session key", valid, testIV, "AAAAAAAAAAAAAAAAAAAAAA==", 0, false},
+		{"Truncated data", valid[:24], testIV, testSessionKey, 0, false},
+		{"Other app", otherApp, testIV, testSessionKey, 0, false},
+		{"Expired", valid, testIV, testSessionKey, time.Minute, false},
+		{"Valid", valid, testIV, testSessionKey, time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, ok := DecryptUser(tt.encryptedData, tt.iv, tt.sessionKey, testAppID, tt.maxAge)
+			if ok != tt.wantValid {
+				t.Fatalf("DecryptUser() = %v, want %v", ok, tt.wantValid)
+			}
+			if ok && (u.OpenID != "oGZUI0egBJY1zhBYw2KhdUfwVJJE" || u.UnionID != "ocMvos6NjeKLIBqg5Mr9QjxrP1FA" || u.NickName != "Band") {
+				t.Errorf("unexpected user: %+v", u)
+			}
+		})
+	}
+}
+
+func TestDecryptPhoneNumber(t *testing.T) {
+	data := encrypt(t, `{"phoneNumber":"+86 13800000000","purePhoneNumber":"13800000000","countryCode":"86","watermark":{"timestamp":1700000000,"appid":"wx4f4bc4dec97d474b"}}`)
+
+	p, ok := DecryptPhoneNumber(data, testIV, testSessionKey, testAppID, 0)
+	if !ok {
+		t.Fatal("DecryptPhoneNumber() = false, want true")
+	}
+	if p.PhoneNumber != "+86 13800000000" || p.PurePhoneNumber != "13800000000" || p.CountryCode != "86" {
+		t.Errorf("unexpected phone number: %+v", p)
+	}
+
+	if _, ok := DecryptPhoneNumber(data, testIV, testSessionKey, "wx0000000000000000", 0); ok {
+		t.Error("DecryptPhoneNumber() must reject a foreign appid")
+	}
+}
+
+func TestUnpad(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []byte
+		want  []byte
+		valid bool
+	}{
+		{"zero pad", []byte{1, 2, 0}, nil, false},
+		{"pad too long", bytes.Repeat([]byte{17}, 17), nil, false},
+		{"inconsistent pad", []byte{1, 3, 2, 3}, nil, false},
+		{"full block", bytes.Repeat([]byte{16}, 16), []byte{}, true},
+		{"valid", []byte{'a', 'b', 2, 2}, []byte("ab"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := unpad(tt.data)
+			if ok != tt.valid || (ok && !bytes.Equal(got, tt.want)) {
+				t.Errorf("unpad() = %v, %v; want %v, %v", got, ok, tt.want, tt.valid)
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyRawData(b *testing.B) {
+	rawData := `{"nickName":"Band","gender":1,"language":"zh_CN","city":"Guangzhou","province":"Guangdong","country":"CN","avatarUrl":"http://wx.qlogo.cn/a.png"}`
+	signature := sign(rawData)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = VerifyRawData(rawData, signature, testSessionKey)
+	}
+}
@@ -0,0 +1,46 @@
+package yandexgames
+
+import (
+	"time"
+
+	"github.com/elum-utils/sign"
+)
+
+// Scheme is the credential scheme of signed Yandex Games player data.
+const Scheme = sign.SchemeYandexGames
+
+// Identity builds a platform-agnostic identity from a verified player.
+func Identity(p *Player) *sign.Identity {
+	id := &sign.Identity{
+		Platform: sign.YandexGames,
+		UserID:   p.UniqueID,
+		Name:     p.PublicName,
+		Language: p.Lang,
+	}
+	if p.AvatarIDHash != "" {
+		id.AvatarURL = "https://games-sdk.yandex.ru/games/api/sdk/v1/player/avatar/" +
+			p.AvatarIDHash + "/islands-retina-medium"
+	}
+	return id
+}
+
+// Verifier verifies player signatures and implements sign.Verifier.
+type Verifier struct {
+	// Secret is the game's secret key
+	Secret string
+
+	// MaxAge limits the age of issuedAt; 0 disables the check
+	MaxAge time.Duration
+}
+
+// Scheme implements sign.Verifier.
+func (v *Verifier) Scheme() string { return Scheme }
+
+// Verify implements sign.Verifier.
+func (v *Verifier) Verify(raw string) (*sign.Identity, bool) {
+	p, ok := VerifyPlayer(raw, v.Secret, v.MaxAge)
+	if !ok || p.UniqueID == "" {
+		return nil, false
+	}
+	return Identity(p), true
+}
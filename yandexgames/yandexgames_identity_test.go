@@ -0,0 +1,29 @@
+package yandexgames
+
+import (
+	"testing"
+
+	"github.com/elum-utils/sign"
+)
+
+func TestVerifier(t *testing.T) {
+	id, ok := (&Verifier{Secret: testSecret}).Verify(testPlayer)
+	if !ok {
+		t.Fatal("Verify() = false, want true")
+	}
+
+	want := sign.Identity{
+		Platform:  sign.YandexGames,
+		UserID:    "Jp7/ZZr3sUlsIfgv",
+		Name:      "Иван",
+		Language:  "ru",
+		AvatarURL: "https://games-sdk.yandex.ru/games/api/sdk/v1/player/avatar/f1a2/islands-retina-medium",
+	}
+	if *id != want {
+		t.Errorf("Identity = %+v, want %+v", *id, want)
+	}
+
+	if _, ok := (&Verifier{Secret: testSecret}).Verify(testPurchases); ok {
+		t.Error("Verify() of purchases = true, want false")
+	}
+}
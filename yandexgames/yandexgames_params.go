@@ -0,0 +1,113 @@
+// Package yandexgames provides types for the signed player and purchase
+// payloads returned by the Yandex Games SDK.
+package yandexgames
+
+import (
+	"encoding/json"
+)
+
+// Signed contains the envelope fields present in every signed payload.
+type Signed struct {
+	// Algorithm is the signature algorithm, always "HMAC-SHA256"
+	Algorithm string `json:"algorithm"`
+
+	// IssuedAt is the Unix timestamp when the payload was signed
+	IssuedAt int64 `json:"issuedAt"`
+
+	// RequestPayload is the developer-provided value passed to the SDK call
+	RequestPayload string `json:"requestPayload"`
+}
+
+// Player is the payload of player.signature from ysdk.getPlayer({signed: true}).
+type Player struct {
+	Signed
+
+	// UniqueID is the permanent player identifier within the game
+	UniqueID string `json:"uniqueID"`
+
+	// PublicName is the player's display name (empty without permission)
+	PublicName string `json:"publicName"`
+
+	// AvatarIDHash identifies the player's avatar image
+	AvatarIDHash string `json:"avatarIdHash"`
+
+	// Lang is the player's interface language code
+	Lang string `json:"lang"`
+
+	// Mode is the authorization mode: "lite" for unauthorized players,
+	// empty for players logged into a Yandex account
+	Mode string `json:"mode"`
+
+	// ScopePermissions lists granted scopes (e.g. "public_name": "allow")
+	ScopePermissions map[string]string `json:"scopePermissions"`
+}
+
+// Purchase is a single in-game purchase.
+type Purchase struct {
+	// ProductID is the product identifier from the developer console
+	ProductID string `json:"productID"`
+
+	// PurchaseToken identifies the purchase for payments.consumePurchase
+	PurchaseToken string `json:"purchaseToken"`
+
+	// DeveloperPayload is the value passed to payments.purchase
+	DeveloperPayload string `json:"developerPayload"`
+}
+
+// Purchases is the payload of signed purchase responses, from
+// payments.getPurchases({signed: true}) or payments.purchase({signed: true}).
+type Purchases struct {
+	Signed
+
+	// Purchases lists the purchases carried by the payload
+	Purchases []Purchase
+}
+
+// envelope mirrors the JSON layout shared by all signed payloads.
+type envelope struct {
+	Signed
+	Data json.RawMessage `json:"data"`
+}
+
+// decodePlayer decodes a JSON payload into a Player.
+func decodePlayer(payload []byte) (*Player, bool) {
+	var env envelope
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return nil, false
+	}
+
+	player := &Player{Signed: env.Signed}
+	if len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, player); err != nil {
+			return nil, false
+		}
+		// data must not override the envelope
+		player.Signed = env.Signed
+	}
+	return player, true
+}
+
+// decodePurchases decodes a JSON payload into Purchases.
+// data may be either an array of purchases or a single purchase.
+func decodePurchases(payload []byte) (*Purchases, bool) {
+	var env envelope
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return nil, false
+	}
+
+	purchases := &Purchases{Signed: env.Signed}
+	switch {
+	case len(env.Data) == 0:
+	case env.Data[0] == '[':
+		if err := json.Unmarshal(env.Data, &purchases.Purchases); err != nil {
+			return nil, false
+		}
+	default:
+		var p Purchase
+		if err := json.Unmarshal(env.Data, &p); err != nil {
+			return nil, false
+		}
+		purchases.Purchases = []Purchase{p}
+	}
+	return purchases, true
+}
@@ -0,0 +1,135 @@
+// Package yandexgames provides functionality for verifying signed payloads
+// returned by the Yandex Games SDK: player.signature and signed purchases.
+package yandexgames
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/elum-utils/sign/internal/utils"
+)
+
+// algorithm is the only signature algorithm used by Yandex Games.
+const algorithm = "HMAC-SHA256"
+
+// now returns the current time; replaced in tests.
+var now = time.Now
+
+// Verify validates a signed payload in the "<base64 signature>.<base64 JSON>"
+// format and returns the decoded JSON.
+//
+// Parameters:
+//   - signature: The signed string returned by the SDK
+//   - secret: The game's secret key from the Yandex Games console
+//
+// Returns:
+//   - []byte: The decoded JSON payload if verification succeeds
+//   - bool: true if signature is valid, false otherwise
+//
+// The signature is HMAC-SHA256 of the base64 JSON part, encoded as standard
+// base64 with padding.
+func Verify(signature, secret string) ([]byte, bool) {
+	// Early return for empty inputs
+	if secret == "" || signature == "" {
+		return nil, false
+	}
+
+	dot := strings.IndexByte(signature, '.')
+	if dot == -1 {
+		return nil, false
+	}
+	sign, data := signature[:dot], signature[dot+1:]
+	if len(sign) != base64.StdEncoding.EncodedLen(sha256.Size) || data == "" {
+		return nil, false
+	}
+
+	// Compute HMAC-SHA256 of the encoded payload
+	mac := utils.GetHMAC(secret)
+	defer utils.PutHMAC(secret, mac)
+	mac.Write([]byte(data))
+
+	var sum [sha256.Size]byte
+	mac.Sum(sum[:0])
+
+	var expected [44]byte // Padded base64 length of a SHA-256 sum
+	base64.StdEncoding.Encode(expected[:], sum[:])
+
+	// Constant-time comparison to prevent timing attacks
+	var diff byte
+	for i := 0; i < len(expected); i++ {
+		diff |= expected[i] ^ sign[i]
+	}
+	if diff != 0 {
+		return nil, false
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, false
+	}
+	return payload, true
+}
+
+// VerifyPlayer validates player.signature and decodes the player.
+//
+// Parameters:
+//   - signature: The player.signature value
+//   - secret: The game's secret key
+//   - maxAge: Maximum allowed difference between issuedAt and the current
+//     time; zero disables the freshness check
+//
+// Returns:
+//   - *Player: The decoded player if verification succeeds
+//   - bool: true if signature is valid and the payload is well-formed
+func VerifyPlayer(signature, secret string, maxAge time.Duration) (*Player, bool) {
+	payload, ok := Verify(signature, secret)
+	if !ok {
+		return nil, false
+	}
+	player, ok := decodePlayer(payload)
+	if !ok || !player.valid(maxAge) {
+		return nil, false
+	}
+	return player, true
+}
+
+// VerifyPurchases validates the signature of a signed purchase response
+// and decodes the purchases.
+//
+// Parameters:
+//   - signature: The signature value of the purchase response
+//   - secret: The game's secret key
+//   - maxAge: Maximum allowed difference between issuedAt and the current
+//     time; zero disables the freshness check
+//
+// Returns:
+//   - *Purchases: The decoded purchases if verification succeeds
+//   - bool: true if signature is valid and the payload is well-formed
+func VerifyPurchases(signature, secret string, maxAge time.Duration) (*Purchases, bool) {
+	payload, ok := Verify(signature, secret)
+	if !ok {
+		return nil, false
+	}
+	purchases, ok := decodePurchases(payload)
+	if !ok || !purchases.valid(maxAge) {
+		return nil, false
+	}
+	return purchases, true
+}
+
+// valid checks the algorithm and, if maxAge is positive, that issuedAt
+// is within maxAge of the current time.
+func (s *Signed) valid(maxAge time.Duration) bool {
+	if s.Algorithm != algorithm {
+		return false
+	}
+	if maxAge > 0 {
+		age := now().Sub(time.Unix(s.IssuedAt, 0))
+		if age > maxAge || age < -maxAge {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package yandexgames
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	testSecret    = "yandex-secret"
+	testPlayer    = "GdvU5bReS2DHbjYph6rSr37gOSi4kCqZIPHUj6Z1BVY=.eyJhbGdvcml0aG0iOiJITUFDLVNIQTI1NiIsImlzc3VlZEF0IjoxNTcxMjMzMzcxLCJyZXF1ZXN0UGF5bG9hZCI6InF3ZSIsImRhdGEiOnsiYXZhdGFySWRIYXNoIjoiZjFhMiIsImxhbmciOiJydSIsInB1YmxpY05hbWUiOiJcdTA0MThcdTA0MzJcdTA0MzBcdTA0M2QiLCJzY29wZVBlcm1pc3Npb25zIjp7ImF2YXRhciI6ImFsbG93IiwicHVibGljX25hbWUiOiJhbGxvdyJ9LCJ1bmlxdWVJRCI6IkpwNy9aWnIzc1Vsc0lmZ3YiLCJtb2RlIjoiIn19"
+	testPurchases = "8fYP2PZnrxCvtZyb2b8S5LLDBbD8qo9f5Ob7iyeGg4Q=.eyJhbGdvcml0aG0iOiJITUFDLVNIQTI1NiIsImlzc3VlZEF0IjoxNTcxMjMzMzcxLCJyZXF1ZXN0UGF5bG9hZCI6InF3ZSIsImRhdGEiOlt7InByb2R1Y3RJRCI6ImdvbGQxMDAiLCJwdXJjaGFzZVRva2VuIjoiYTEwZTVhYTgtN2QxZS00YzFjLTljMmEtM2IyZTFmMGE5ZDhjIiwiZGV2ZWxvcGVyUGF5bG9hZCI6Im9yZGVyLTEifSx7InByb2R1Y3RJRCI6Im5vYWRzIiwicHVyY2hhc2VUb2tlbiI6ImIyIiwiZGV2ZWxvcGVyUGF5bG9hZCI6IiJ9XX0="
+	testPurchase  = "uy/fs4F94+G/XVUAToFgQC6LjktoDb45JqFmkdiFgOg=.eyJhbGdvcml0aG0iOiJITUFDLVNIQTI1NiIsImlzc3VlZEF0IjoxNTcxMjMzMzcxLCJyZXF1ZXN0UGF5bG9hZCI6IiIsImRhdGEiOnsicHJvZHVjdElEIjoiZ29sZDEwMCIsInB1cmNoYXNlVG9rZW4iOiJ0MSIsImRldmVsb3BlclBheWxvYWQiOiJwIn19"
+	testSHA1      = "ZcDFWySksnwxbDuY7aS00GMtqJJxWUujHMZcZR4zlxA=.eyJhbGdvcml0aG0iOiJITUFDLVNIQTEiLCJpc3N1ZWRBdCI6MTU3MTIzMzM3MSwiZGF0YSI6eyJ1bmlxdWVJRCI6IngifX0="
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		signature string
+		secret    string
+		wantValid bool
+	}{
+		{"Missing secret", testPlayer, "", false},
+		{"Empty signature", "", testSecret, false},
+		{"No separator", "GdvU5bReS2DHbjYph6rSr37gOSi4kCqZIPHUj6Z1BVY=", testSecret, false},
+		{"Empty payload", "GdvU5bReS2DHbjYph6rSr37gOSi4kCqZIPHUj6Z1BVY=.", testSecret, false},
+		{"Short signature", "abc." + testPlayer[45:], testSecret, false},
+		{"Wrong secret", testPlayer, "other-secret", false},
+		{"Tampered payload", testPlayer[:len(testPlayer)-4] + "fQ==", testSecret, false},
+		{"Valid player", testPlayer, testSecret, true},
+		{"Valid purchases", testPurchases, testSecret, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, ok := Verify(tt.signature, tt.secret)
+			if ok != tt.wantValid {
+				t.Errorf("Verify() = %v, want %v", ok, tt.wantValid)
+			}
+			if ok && len(payload) == 0 {
+				t.Error("Expected non-empty payload on valid signature")
+			}
+		})
+	}
+}
+
+func TestVerifyPlayer(t *testing.T) {
+	p, ok := VerifyPlayer(testPlayer, testSecret, 0)
+	if !ok {
+		t.Fatal("VerifyPlayer() = false, want true")
+	}
+	if p.UniqueID != "Jp7/ZZr3sUlsIfgv" || p.PublicName != "Иван" || p.Lang != "ru" || p.Mode != "" {
+		t.Errorf("unexpected player: %+v", p)
+	}
+	if p.IssuedAt != 1571233371 || p.RequestPayload != "qwe" || p.Algorithm != "HMAC-SHA256" {
+		t.Errorf("unexpected envelope: %+v", p.Signed)
+	}
+	if p.ScopePermissions["public_name"] != "allow" {
+		t.Errorf("unexpected permissions: %v", p.ScopePermissions)
+	}
+
+	if _, ok := VerifyPlayer(testSHA1, testSecret, 0); ok {
+		t.Error("VerifyPlayer() must reject algorithms other than HMAC-SHA256")
+	}
+}
+
+func TestVerifyPurchases(t *testing.T) {
+	list, ok := VerifyPurchases(testPurchases, testSecret, 0)
+	if !ok {
+		t.Fatal("VerifyPurchases() = false, want true")
+	}
+	if len(list.Purchases) != 2 {
+		t.Fatalf("len(Purchases) = %d, want 2", len(list.Purchases))
+	}
+	want := Purchase{
+		ProductID:        "gold100",
+		PurchaseToken:    "a10e5aa8-7d1e-4c1c-9c2a-3b2e1f0a9d8c",
+		DeveloperPayload: "order-1",
+	}
+	if list.Purchases[0] != want {
+		t.Errorf("Purchases[0] = %+v, want %+v", list.Purchases[0], want)
+	}
+
+	single, ok := VerifyPurchases(testPurchase, testSecret, 0)
+	if !ok {
+		t.Fatal("VerifyPurchases() on a single purchase = false, want true")
+	}
+	if len(single.Purchases) != 1 || single.Purchases[0].PurchaseToken != "t1" {
+		t.Errorf("unexpected single purchase: %+v", single.Purchases)
+	}
+}
+
+func TestVerify_MaxAge(t *testing.T) {
+	issued := time.Unix(1571233371, 0)
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		maxAge time.Duration
+		want   bool
+	}{
+		{"Fresh", issued.Add(time.Minute), time.Hour, true},
+		{"Stale", issued.Add(2 * time.Hour), time.Hour, false},
+		{"Issued in the future", issued.Add(-2 * time.Hour), time.Hour, false},
+		{"Check disabled", issued.Add(24 * time.Hour), 0, true},
+	}
+	for _, tt := range tests {
+		now = func() time.Time { return tt.now }
+		if _, ok := VerifyPlayer(testPlayer, testSecret, tt.maxAge); ok != tt.want {
+			t.Errorf("%s: VerifyPlayer() = %v, want %v", tt.name, ok, tt.want)
+		}
+		if _, ok := VerifyPurchases(testPurchases, testSecret, tt.maxAge); ok != tt.want {
+			t.Errorf("%s: VerifyPurchases() = %v, want %v", tt.name, ok, tt.want)
+		}
+	}
+
+	// The Verifier applies its MaxAge
+	now = func() time.Time { return issued.Add(2 * time.Hour) }
+	if _, ok := (&Verifier{Secret: testSecret, MaxAge: time.Hour}).Verify(testPlayer); ok {
+		t.Error("Verifier.Verify() of a stale player = true, want false")
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Verify(testPlayer, testSecret)
+	}
+}
+
+func BenchmarkVerifyPlayer(b *testing.B) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = VerifyPlayer(testPlayer, testSecret, 0)
+	}
+}